@@ -213,9 +213,11 @@ func AggFilterName(name string) AggFilter {
 }
 
 type aggFilter struct {
-	name   string
-	terms  map[string]interface{}
-	ranges map[string]interface{}
+	name    string
+	terms   map[string]interface{}
+	ranges  map[string]interface{}
+	exists  string
+	missing string
 }
 
 func (a aggFilter) Name(name string) AggFilter {
@@ -248,12 +250,28 @@ func (a aggFilter) Range(field string, r RangeFilter) AggFilter {
 	return a
 }
 
+// Exists bucket of documents field has value
+func (a aggFilter) Exists(field string) AggFilter {
+	a.exists = field
+	return a
+}
+
+// Missing bucket of documents field has no value
+func (a aggFilter) Missing(field string) AggFilter {
+	a.missing = field
+	return a
+}
+
 func (a aggFilter) Result() (string, map[string]interface{}) {
 	result := make(map[string]interface{}, 0)
 	if a.terms != nil {
 		result["terms"] = a.terms
 	} else if a.ranges != nil {
 		result["range"] = a.ranges
+	} else if a.exists != "" {
+		result["exists"] = map[string]string{"field": a.exists}
+	} else if a.missing != "" {
+		result["bool"] = esQueryBool{Not: []interface{}{exists{name: a.missing}}}
 	}
 	return a.name, result
 }
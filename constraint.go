@@ -3,6 +3,7 @@ package ges
 import (
 	"context"
 	"encoding/json"
+	"time"
 )
 
 /***************************
@@ -21,20 +22,39 @@ const (
 type Client interface {
 	IndexName(name string) Client
 	Index() Index
+	Routing(routing string) Client
+	TermsStrategy(strategy TermsStrategy) Client
+	RuntimeField(name string, fieldType MappingType, script Script) Client
+	// RegisterQuery store query to percolator field of document id
+	RegisterQuery(ctx context.Context, field, id string, query Filter) error
+	// Percolate id of stored queries match any of docs
+	Percolate(ctx context.Context, field string, docs ...interface{}) ([]string, error)
+	IndexPattern(prefix string, interval IndexInterval, tz *time.Location) Client
+	TimeField(field string) Client
+	Rollover(ctx context.Context, alias string, conditions RolloverConditions) (RolloverResult, error)
 
 	AdjustPurePegative(v bool) Client
 	Not(filters ...Filter) Client
 	Where(filters ...Filter) Client
+	Filter(filters ...Filter) Client
+	// SQLWhere sql where clause with ? placeholder as Where conditions
+	SQLWhere(query string, args ...interface{}) Client
 	Or(filters ...Filter) Client
+	MinimumShouldMatch(minimumShouldMatch string) Client
 	OrderBy(field string, isDesc bool) Client
+	OrderByGeoDistance(field string, point GeoPoint, isDesc bool, unit string) Client
 	Size(uint64) Client
 	Agg(aggs ...Agg) Client
 	Start(uint64) Client
 	Limit(uint64, uint64) Client
 	Limit64(int64, int64) Client
 	Fields(...string) Client
+	// Validate check fields of conditions, sorts, aggs and fields against index mapping
+	Validate(ctx context.Context) ([]ValidationFinding, error)
 	Search(ctx context.Context, result interface{}) (uint64, error)
 	SearchResultHits(ctx context.Context) ([]SearchResultHitResult, uint64, error)
+	// SearchWithInnerHits search and return inner hits of each hit, key is _id
+	SearchWithInnerHits(ctx context.Context, result interface{}) (map[string]HitInnerHits, uint64, error)
 	GetById(ctx context.Context, id string, result interface{}) error
 	RawSQL(ctx context.Context, sql string, result interface{}) error
 	Count(ctx context.Context) (uint64, error)
@@ -51,22 +71,64 @@ type Client interface {
 	DeleteById(ctx context.Context, ids ...string) error
 }
 
+// ScoreFunction function of function_score query
+type ScoreFunction interface {
+	Filter(filter Filter) ScoreFunction
+	Weight(weight float64) ScoreFunction
+	Result() map[string]interface{}
+}
+
 type Filter interface {
 	Match(field string, value interface{}) Filter
+	MatchWith(field string, value interface{}, opts ...MatchOptions) Filter
+	MatchPhrase(field string, text string, opts ...MatchOptions) Filter
+	MatchPhrasePrefix(field string, text string, opts ...MatchOptions) Filter
+	MultiMatch(text string, fields []string, opts ...MatchOptions) Filter
+	QueryString(query string, opts ...MatchOptions) Filter
+	SimpleQueryString(query string, opts ...MatchOptions) Filter
 	Term(field string, value interface{}) Filter
 	Terms(field string, values interface{}) Filter
 	TermsSingeItem(field string, value interface{}) Filter
-	Between(field string, start, end int64) Filter
+	Between(field string, start, end interface{}) Filter
 	FromTo(field string, from, to interface{}) Filter
 	Range(field string, from, to interface{}) Filter
-	Gt(field string, value int64) Filter
-	Gte(field string, value int64) Filter
-	Lt(field string, value int64) Filter
-	Lte(field string, value int64) Filter
-	Wildcard(field string, value string) Filter
-	WildcardSuffix(field string, value string) Filter
+	RangeWith(field string, r RangeFilter) Filter
+	Gt(field string, value interface{}) Filter
+	Gte(field string, value interface{}) Filter
+	Lt(field string, value interface{}) Filter
+	Lte(field string, value interface{}) Filter
+	Wildcard(field string, value string, opts ...TermOptions) Filter
+	WildcardSuffix(field string, value string, opts ...TermOptions) Filter
+	Prefix(field string, value string, opts ...TermOptions) Filter
+	Regexp(field string, value string, opts ...TermOptions) Filter
+	Fuzzy(field string, value string, opts ...TermOptions) Filter
+	Ids(values ...string) Filter
+	TermsSet(field string, terms interface{}, opts TermsSetOptions) Filter
+	TermsLookup(field, index, id, path string) Filter
+	TermsLookupWith(field string, lookup TermsLookupValue) Filter
+	Script(script Script) Filter
+	Percolate(field string, docs ...interface{}) Filter
+	MoreLikeThis(fields []string, likeTexts []string, likeDocs []DocRef, opts ...MoreLikeThisOptions) Filter
+	Exists(field string) Filter
+	Missing(field string) Filter
 	BoolItem(must, not, should, match Filter, adjustPureNegative bool) Filter
+	BoolWith(must, not, should, match Filter, opts BoolOptions) Filter
+	BoolFilter(filters ...Filter) Filter
+	MinimumShouldMatch(minimumShouldMatch string, should ...Filter) Filter
 	Nested(nestedFilter NestedFilter) Filter
+	GeoDistance(field string, lat, lon float64, distance string) Filter
+	GeoBoundingBox(field string, topLeft, bottomRight GeoPoint) Filter
+	GeoPolygon(field string, points ...GeoPoint) Filter
+	GeoShape(field string, shape GeoGeometry, relation GeoShapeRelation) Filter
+	FunctionScore(query Filter, functions ...ScoreFunction) Filter
+	FunctionScoreWith(query Filter, opts FunctionScoreOptions, functions ...ScoreFunction) Filter
+	ScriptScore(query Filter, script Script, minScore ...float64) Filter
+	ConstantScore(filter Filter, boost float64) Filter
+	Boosting(positive, negative Filter, negativeBoost float64) Filter
+	DisMax(tieBreaker float64, queries ...Filter) Filter
+	HasChild(childType string, query Filter, opts ...JoinOptions) Filter
+	HasParent(parentType string, query Filter, opts ...JoinOptions) Filter
+	ParentID(childType, id string) Filter
 	Append(filters ...Filter) Filter
 	Result() []interface{}
 }
@@ -78,12 +140,21 @@ type RangeFilter interface {
 	Gte(value interface{}) RangeFilter
 	Lt(value interface{}) RangeFilter
 	Lte(value interface{}) RangeFilter
+	Format(format string) RangeFilter
+	TimeZone(timeZone string) RangeFilter
+	Relation(relation RangeRelation) RangeFilter
+	Boost(boost float64) RangeFilter
 }
 
 type NestedFilter interface {
 	Must(filters ...Filter) NestedFilter
 	Should(filters ...Filter) NestedFilter
 	Not(filters ...Filter) NestedFilter
+	Filter(filters ...Filter) NestedFilter
+	MinimumShouldMatch(minimumShouldMatch string) NestedFilter
+	ScoreMode(scoreMode string) NestedFilter
+	IgnoreUnmapped(ignoreUnmapped bool) NestedFilter
+	InnerHits(innerHits InnerHits) NestedFilter
 	Path(path string) NestedFilter
 	Match(filters ...Filter) NestedFilter
 	Result() interface{}
@@ -94,6 +165,24 @@ type Index interface {
 	Create(ctx context.Context, mapping IndexMeta) error
 	List(ctx context.Context) ([]error, error)
 	Mapping(ctx context.Context) (map[string]indexMetaResp, error)
+
+	PutLifecyclePolicy(ctx context.Context, policy string, p ILMPolicy) error
+	GetLifecyclePolicy(ctx context.Context, policy string) (ILMPolicy, error)
+	DeleteLifecyclePolicy(ctx context.Context, policy string) error
+	AttachLifecyclePolicy(ctx context.Context, policy, rolloverAlias string) error
+	ExplainLifecycle(ctx context.Context) (map[string]ILMExplain, error)
+
+	PutIndexTemplate(ctx context.Context, name string, template IndexTemplate) error
+	DeleteIndexTemplate(ctx context.Context, name string) error
+	CreateDataStream(ctx context.Context) error
+	DeleteDataStream(ctx context.Context) error
+	GetDataStream(ctx context.Context) ([]DataStream, error)
+
+	Analyze(ctx context.Context, analyzer, text string) ([]AnalyzeToken, error)
+
+	UpdateSettings(ctx context.Context, settings IndexDynamicSettings) error
+	Refresh(ctx context.Context) error
+	BulkLoadMode(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type Agg interface {
@@ -116,6 +205,8 @@ type AggFilter interface {
 	Terms(field string, val ...interface{}) AggFilter
 	TermsArray(field string, val interface{}) AggFilter
 	Range(field string, f RangeFilter) AggFilter
+	Exists(field string) AggFilter
+	Missing(field string) AggFilter
 	Result() (string, map[string]interface{})
 }
 
@@ -148,6 +239,26 @@ type SearchResultHitResult struct {
 	Id     string          `json:"_id"`
 	Score  float64         `json:"_score"`
 	Source json.RawMessage `json:"_source"`
+	// Sort sort values of hit. eg: distance of OrderByGeoDistance
+	Sort []interface{} `json:"sort,omitempty"`
+	// InnerHits matched nested, child or parent documents, key is name of inner hits
+	InnerHits HitInnerHits `json:"inner_hits,omitempty"`
+	// Fields values of fields in body. eg: runtime field
+	Fields map[string]json.RawMessage `json:"fields,omitempty"`
+}
+
+// HitInnerHits inner hits of one hit, key is name of inner hits. default name is nested path, child or parent type
+type HitInnerHits map[string]SearchInnerHits
+
+// SearchInnerHits inner hits of nested, has_child, has_parent query
+type SearchInnerHits struct {
+	Hits struct {
+		Total struct {
+			Value    uint64 `json:"value"`
+			Relation string `json:"relation"`
+		} `json:"total"`
+		IndexHits []SearchResultHitResult `json:"hits"`
+	} `json:"hits"`
 }
 
 type CountResult struct {
@@ -196,6 +307,14 @@ type MultiUpdate interface {
 type IndexMeta struct {
 	Settings *IndexMappingSettings `json:"settings,omitempty"`
 	Mappings IndexMapping          `json:"mappings"`
+	Aliases  map[string]IndexAlias `json:"aliases,omitempty"`
+}
+
+type IndexAlias struct {
+	IsWriteIndex *bool `json:"is_write_index,omitempty"`
+	// Filter only document matched filter visible by alias
+	Filter  interface{} `json:"filter,omitempty"`
+	Routing string      `json:"routing,omitempty"`
 }
 
 type IndexMapping struct {
@@ -205,6 +324,17 @@ type IndexMapping struct {
 type MappingField struct {
 	Type       MappingType             `json:"type"`
 	Properties map[string]MappingField `json:"properties,omitempty"`
+	// Fields multi-fields, same value indexed with different way. eg: text field with keyword sub field
+	Fields         map[string]MappingField `json:"fields,omitempty"`
+	Analyzer       string                  `json:"analyzer,omitempty"`
+	SearchAnalyzer string                  `json:"search_analyzer,omitempty"`
+	// Normalizer only for keyword field
+	Normalizer string `json:"normalizer,omitempty"`
+	// Format date format of date field. eg: yyyy-MM-dd HH:mm:ss||epoch_millis
+	Format string `json:"format,omitempty"`
+	// Relations parent/child relations of join field, value is child name or names. eg: {"question": ["answer", "comment"]}
+	Relations           map[string]interface{} `json:"relations,omitempty"`
+	EagerGlobalOrdinals *bool                  `json:"eager_global_ordinals,omitempty"`
 }
 
 type MappingType string
@@ -234,6 +364,10 @@ const (
 	MappingTypeDate         MappingType = "date"
 	MappingTypeNested       MappingType = "nested"
 	MappingTypeText         MappingType = "text"
+	// MappingTypeJoin parent/child relation in same index, child must route to shard of parent
+	MappingTypeJoin MappingType = "join"
+	// MappingTypePercolator field store query, used by Percolate
+	MappingTypePercolator MappingType = "percolator"
 )
 
 type indexMetaResp struct {
@@ -254,7 +388,14 @@ type IndexSettings struct {
 	Version          *struct {
 		Created string `json:"created,omitempty"`
 	} `json:"version,omitempty"`
-	ProvidedName string `json:"provided_name,omitempty"`
+	ProvidedName string                  `json:"provided_name,omitempty"`
+	Lifecycle    *IndexLifecycleSettings `json:"lifecycle,omitempty"`
+	Analysis     *IndexAnalysis          `json:"analysis,omitempty"`
+}
+
+type IndexLifecycleSettings struct {
+	Name          string `json:"name,omitempty"`
+	RolloverAlias string `json:"rollover_alias,omitempty"`
 }
 
 type IndexMetaRespMappings struct {
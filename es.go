@@ -24,8 +24,8 @@ import (
 
 type es struct {
 	isAgg bool
-	// 	  field:desc
-	sorts              []string
+	// sorts sort of request body. eg: {"field": {"order": "desc"}}
+	sorts              []interface{}
 	fields             []string
 	indexName          string
 	from               uint64
@@ -33,6 +33,17 @@ type es struct {
 	cond               cond
 	agg                map[string]interface{}
 	adjustPureNegative bool
+	minimumShouldMatch string
+	// indexPattern time based index name, read only, share with clone
+	indexPattern *indexPattern
+	timeField    string
+	// routing custom routing of read and write. eg: parent id of join field
+	routing       string
+	termsStrategy TermsStrategy
+	// runtimeMappings search time runtime fields, key is field name
+	runtimeMappings map[string]RuntimeField
+	// err deferred error of building condition. eg: SQLWhere syntax error, returned when request
+	err error
 }
 
 type cond struct {
@@ -40,21 +51,14 @@ type cond struct {
 	filter []interface{}
 	should []interface{}
 	not    []interface{}
-	exists []interface{}
 }
 
 func (e es) MarshalJSON() ([]byte, error) {
-	cond := esCondition{
-		Query: esConditionQuery{Bool: esQueryBool{
-			Must:               e.cond.must,
-			Not:                e.cond.not,
-			Should:             e.cond.should,
-			AdjustPureNegative: e.adjustPureNegative,
-		}},
-		Agg: e.agg,
+	if e.err != nil {
+		return nil, e.err
 	}
 	queryBody := &bytes.Buffer{}
-	if err := json.NewEncoder(queryBody).Encode(cond); err != nil {
+	if err := json.NewEncoder(queryBody).Encode(e.condition()); err != nil {
 		return nil, err
 	}
 	return queryBody.Bytes(), nil
@@ -65,14 +69,64 @@ func (e es) IndexName(name string) Client {
 	return e
 }
 
+// Routing custom routing value. write route document to shard, read only search shard of routing.
+// child documents of join field must use routing of parent
+func (e es) Routing(routing string) Client {
+	e = e.Clone()
+	e.routing = routing
+	return e
+}
+
+// docRouting routing of document, NewChildDoc routing first
+func (e es) docRouting(d Document) string {
+	if rd, ok := d.(interface{ Routing() string }); ok && rd.Routing() != "" {
+		return rd.Routing()
+	}
+	return e.routing
+}
+
 func (e es) Clone() es {
 	newE := es{}
 	if err := mapper.AllMapper(context.TODO(), e, &newE); err != nil {
 		panic("clone es error" + fmt.Sprintf("%#v", err))
 	}
+	newE.indexPattern = e.indexPattern
+	newE.err = e.err
 	return newE
 }
 
+// IndexPattern time based index name, prefix + time of document TimeField formatted by interval.
+// write pick index from document, search pick indices from Range condition of TimeField
+func (e es) IndexPattern(prefix string, interval IndexInterval, tz *time.Location) Client {
+	e = e.Clone()
+	e.indexPattern = newIndexPattern(prefix, interval, tz)
+	return e
+}
+
+// TimeField document time field of IndexPattern, default @timestamp
+func (e es) TimeField(field string) Client {
+	e = e.Clone()
+	e.timeField = field
+	return e
+}
+
+func (e es) patternTimeField() string {
+	if e.timeField == "" {
+		return DefaultIndexPatternTimeField
+	}
+	return e.timeField
+}
+
+// searchIndices index names of read request
+func (e es) searchIndices() []string {
+	if e.indexPattern == nil {
+		return []string{e.indexName}
+	}
+	clauses := make([]interface{}, 0, len(e.cond.must)+len(e.cond.filter))
+	clauses = append(append(clauses, e.cond.must...), e.cond.filter...)
+	return e.indexPattern.searchIndices(e.patternTimeField(), clauses)
+}
+
 func (e es) Index() Index {
 	index := esIndex{name: e.indexName}
 	return index
@@ -95,6 +149,8 @@ func (e es) Not(filters ...Filter) Client {
 	return e
 }
 
+// Where query context, conditions affect document score.
+// exact match conditions not need score should use Filter
 func (e es) Where(filters ...Filter) Client {
 	e = e.Clone()
 	for _, filter := range filters {
@@ -107,6 +163,20 @@ func (e es) Where(filters ...Filter) Client {
 	return e
 }
 
+// Filter filter context(bool.filter), conditions not affect document score and can be cached
+func (e es) Filter(filters ...Filter) Client {
+	e = e.Clone()
+	for _, filter := range filters {
+		if filter == nil {
+			continue
+		}
+		e.cond.filter = append(e.cond.filter, filter.Result()...)
+	}
+	return e
+}
+
+// Or conditions as a disjunction group, at least one of them must match.
+// use MinimumShouldMatch change the number of conditions must match
 func (e es) Or(filters ...Filter) Client {
 	e = e.Clone()
 	for _, filter := range filters {
@@ -118,13 +188,38 @@ func (e es) Or(filters ...Filter) Client {
 	return e
 }
 
+// MinimumShouldMatch number or percentage of Or conditions must match. eg: 2, 75%, -1
+func (e es) MinimumShouldMatch(minimumShouldMatch string) Client {
+	e = e.Clone()
+	e.minimumShouldMatch = minimumShouldMatch
+	return e
+}
+
 func (e es) OrderBy(field string, isDesc bool) Client {
 	e = e.Clone()
+	order := "asc"
 	if isDesc {
-		e.sorts = append(e.sorts, field+":desc")
-	} else {
-		e.sorts = append(e.sorts, field+":asc")
+		order = "desc"
 	}
+	e.sorts = append(e.sorts, map[string]esConditionSortOrder{field: {Order: order}})
+	return e
+}
+
+// OrderByGeoDistance sort by distance between geo_point field and point, unit eg: km, m. sort value is distance
+func (e es) OrderByGeoDistance(field string, point GeoPoint, isDesc bool, unit string) Client {
+	e = e.Clone()
+	order := "asc"
+	if isDesc {
+		order = "desc"
+	}
+	geoSort := map[string]interface{}{
+		field:   point,
+		"order": order,
+	}
+	if unit != "" {
+		geoSort["unit"] = unit
+	}
+	e.sorts = append(e.sorts, map[string]interface{}{"_geo_distance": geoSort})
 	return e
 }
 
@@ -192,7 +287,37 @@ func (e es) SearchResultHits(ctx context.Context) ([]SearchResultHitResult, uint
 	return resp.Hits.IndexHits, resp.Hits.Total.Value, nil
 }
 
-func (e es) searchHelper(ctx context.Context) (*esapi.Response, error) {
+// SearchWithInnerHits decode hits to result like Search, inner hits of hit returned by map, key is _id
+func (e es) SearchWithInnerHits(ctx context.Context, result interface{}) (map[string]HitInnerHits, uint64, error) {
+	resultV := reflect.ValueOf(result)
+	if resultV.Kind() != reflect.Ptr || resultV.Elem().Kind() != reflect.Slice {
+		return nil, 0, fmt.Errorf("results argument must be a slice address")
+	}
+	res, err := e.searchHelper(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unexpected error when get: %s", err)
+	}
+	defer res.Body.Close()
+
+	resp, err := parseSearchRespDefaultDecode(ctx, res)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := e.parseSearchRespResultArray(ctx, resp, resultV); err != nil {
+		return nil, 0, err
+	}
+
+	innerHits := make(map[string]HitInnerHits, len(resp.Hits.IndexHits))
+	for _, hit := range resp.Hits.IndexHits {
+		if len(hit.InnerHits) != 0 {
+			innerHits[hit.Id] = hit.InnerHits
+		}
+	}
+	return innerHits, resp.Hits.Total.Value, nil
+}
+
+// searchHelper search request of e, opts appended after options of e
+func (e es) searchHelper(ctx context.Context, opts ...func(*esapi.SearchRequest)) (*esapi.Response, error) {
 
 	queryBody, err := e.buildQuery(ctx)
 	if err != nil {
@@ -201,8 +326,15 @@ func (e es) searchHelper(ctx context.Context) (*esapi.Response, error) {
 
 	searchOpts := []func(*esapi.SearchRequest){
 		rawESClient.Search.WithContext(ctx),
-		rawESClient.Search.WithIndex(e.indexName),
-		rawESClient.Search.WithSort(e.sorts...),
+		rawESClient.Search.WithIndex(e.searchIndices()...),
+	}
+	if e.routing != "" {
+		searchOpts = append(searchOpts, rawESClient.Search.WithRouting(e.routing))
+	}
+	if e.indexPattern != nil {
+		searchOpts = append(searchOpts,
+			rawESClient.Search.WithIgnoreUnavailable(true),
+			rawESClient.Search.WithAllowNoIndices(true))
 	}
 	if queryBody.Len() > 0 {
 		searchOpts = append(searchOpts, rawESClient.Search.WithBody(queryBody))
@@ -222,22 +354,68 @@ func (e es) searchHelper(ctx context.Context) (*esapi.Response, error) {
 	}
 
 	return rawESClient.Search(
-		searchOpts...,
+		append(searchOpts, opts...)...,
 	)
 }
 
+func (e es) condition() esCondition {
+	b := esQueryBool{
+		Must:               e.cond.must,
+		Filter:             e.cond.filter,
+		Not:                e.cond.not,
+		AdjustPureNegative: e.adjustPureNegative,
+	}
+	switch {
+	case len(e.cond.should) == 0:
+	case e.minimumShouldMatch != "":
+		b.Should, b.MinimumShouldMatch = e.cond.should, e.minimumShouldMatch
+	case len(e.cond.must) == 0 && len(e.cond.filter) == 0:
+		b.Should = e.cond.should
+	default:
+		// should is optional when bool has must or filter, Or conditions must match at least one
+		b.Must = make([]interface{}, 0, len(e.cond.must)+1)
+		b.Must = append(b.Must, e.cond.must...)
+		b.Must = append(b.Must, boolFilter{Bool: esQueryBool{Should: e.cond.should, MinimumShouldMatch: "1"}})
+	}
+	return esCondition{
+		Query:           esConditionQuery{Bool: b},
+		Agg:             e.agg,
+		Sort:            e.sorts,
+		RuntimeMappings: e.runtimeMappings,
+		Fields:          e.runtimeFields(),
+	}
+}
+
 func (e es) buildQuery(ctx context.Context) (*bytes.Buffer, error) {
-	cond := esCondition{
-		Query: esConditionQuery{Bool: esQueryBool{
-			Must:               e.cond.must,
-			Not:                e.cond.not,
-			Should:             e.cond.should,
-			AdjustPureNegative: e.adjustPureNegative,
-		}},
-		Agg: e.agg,
+	if e.err != nil {
+		return nil, e.err
+	}
+	e, err := e.rewriteTerms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	queryBody := &bytes.Buffer{}
+	if err := json.NewEncoder(queryBody).Encode(e.condition()); err != nil {
+		return nil, fmt.Errorf("search condition build error. %s", err.Error())
+	}
+
+	return queryBody, nil
+}
+
+// buildQueryOnly request body only has query, count and delete_by_query not support sort, aggs and runtime_mappings
+func (e es) buildQueryOnly(ctx context.Context) (*bytes.Buffer, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	if len(e.runtimeMappings) != 0 {
+		return nil, fmt.Errorf("runtime field not supported by count and delete by query")
+	}
+	e, err := e.rewriteTerms(ctx)
+	if err != nil {
+		return nil, err
 	}
 	queryBody := &bytes.Buffer{}
-	if err := json.NewEncoder(queryBody).Encode(cond); err != nil {
+	if err := json.NewEncoder(queryBody).Encode(esCondition{Query: e.condition().Query}); err != nil {
 		return nil, fmt.Errorf("search condition build error. %s", err.Error())
 	}
 
@@ -279,6 +457,9 @@ func (e es) RawSQL(ctx context.Context, sql string, result interface{}) error {
 }
 
 func (e es) GetById(ctx context.Context, id string, result interface{}) error {
+	if e.indexPattern != nil {
+		return e.patternGetById(ctx, id, result)
+	}
 
 	res, err := rawESClient.GetSource(
 		e.indexName,
@@ -286,6 +467,7 @@ func (e es) GetById(ctx context.Context, id string, result interface{}) error {
 		rawESClient.GetSource.WithContext(ctx),
 		rawESClient.GetSource.WithPretty(),
 		rawESClient.GetSource.WithSourceIncludes(e.fields...),
+		rawESClient.GetSource.WithRouting(e.routing),
 	)
 	if err != nil {
 		return err
@@ -298,7 +480,11 @@ func (e es) GetById(ctx context.Context, id string, result interface{}) error {
 
 // UpdateById
 func (e es) UpdateById(ctx context.Context, id string, data interface{}) error {
-	bufferBody := bytes.NewBufferString(fmt.Sprintf(`{"update": {"_id": "%s"}}`, id))
+	meta, err := e.docMeta(id, e.routing, data)
+	if err != nil {
+		return err
+	}
+	bufferBody := bytes.NewBufferString(bulkActionLine("update", meta))
 	bufferBody.WriteString("\n")
 
 	// encode 会自动加上换行符
@@ -335,7 +521,11 @@ func (e es) MUpdateById(ctx context.Context, docs ...Document) error {
 	jd := json.NewEncoder(bufferBody)
 	for _, doc := range docs {
 		id, data := doc.Item()
-		bufferBody.WriteString(fmt.Sprintf(`{"update": {"_id": "%s"}}`, id))
+		meta, err := e.docMeta(id, e.docRouting(doc), data)
+		if err != nil {
+			return err
+		}
+		bufferBody.WriteString(bulkActionLine("update", meta))
 		bufferBody.WriteString("\n")
 		// encode 会自动加上换行符
 		if err := jd.Encode(mapStrAny{"doc": data}); err != nil {
@@ -371,12 +561,16 @@ func (e es) MUpsertById(ctx context.Context, docs ...Document) error {
 	jd := json.NewEncoder(bufferBody)
 	for _, doc := range docs {
 		id, data := doc.Item()
+		meta, err := e.docMeta(id, e.docRouting(doc), data)
+		if err != nil {
+			return err
+		}
 		//var newData interface{}
 		if id == "" {
-			bufferBody.WriteString(`{"index": {}}`)
+			bufferBody.WriteString(bulkActionLine("index", meta))
 
 		} else {
-			bufferBody.WriteString(fmt.Sprintf(`{"update": {"_id": "%s"}}`, id))
+			bufferBody.WriteString(bulkActionLine("update", meta))
 			data = mapStrAny{"doc": data, "doc_as_upsert": true}
 		}
 		bufferBody.WriteString("\n")
@@ -423,11 +617,15 @@ func (e es) USave(ctx context.Context, docs ...Document) error {
 		var newData interface{}
 		id := doc.ID()
 		data := doc.Doc()
+		meta, err := e.docMeta(id, e.docRouting(doc), data)
+		if err != nil {
+			return err
+		}
 		if id == "" {
-			bufferBody.WriteString(`{"index": {}}`)
+			bufferBody.WriteString(bulkActionLine("index", meta))
 			newData = data
 		} else {
-			bufferBody.WriteString(fmt.Sprintf(`{"update": {"_id": "%s"}}`, id))
+			bufferBody.WriteString(bulkActionLine("update", meta))
 			newData = mapStrAny{"doc": data}
 		}
 		bufferBody.WriteString("\n")
@@ -465,17 +663,24 @@ func (e es) DeleteById(ctx context.Context, ids ...string) error {
 // Delete delete_by_query
 func (e es) Delete(ctx context.Context) error {
 
-	queryBody, err := e.buildQuery(ctx)
+	queryBody, err := e.buildQueryOnly(ctx)
 	if err != nil {
 		return err
 	}
 
-	res, err := rawESClient.DeleteByQuery(
-		[]string{e.indexName},
-		queryBody,
-		rawESClient.DeleteByQuery.WithTimeout(20*time.Second),
+	opts := []func(*esapi.DeleteByQueryRequest){
+		rawESClient.DeleteByQuery.WithTimeout(20 * time.Second),
 		rawESClient.DeleteByQuery.WithRefresh(true),
-	)
+	}
+	if e.routing != "" {
+		opts = append(opts, rawESClient.DeleteByQuery.WithRouting(e.routing))
+	}
+	if e.indexPattern != nil {
+		opts = append(opts,
+			rawESClient.DeleteByQuery.WithIgnoreUnavailable(true),
+			rawESClient.DeleteByQuery.WithAllowNoIndices(true))
+	}
+	res, err := rawESClient.DeleteByQuery(e.searchIndices(), queryBody, opts...)
 	if err != nil {
 		return fmt.Errorf("unexpected error when get: %s", err)
 	}
@@ -487,16 +692,27 @@ func (e es) Delete(ctx context.Context) error {
 }
 
 func (e es) Count(ctx context.Context) (uint64, error) {
+	if len(e.runtimeMappings) != 0 {
+		return e.countBySearch(ctx)
+	}
 	e.size = 0
-	queryBody, err := e.buildQuery(ctx)
+	queryBody, err := e.buildQueryOnly(ctx)
 	if err != nil {
 		return 0, err
 	}
 	opts := []func(*esapi.CountRequest){
-		rawESClient.Count.WithIndex(e.indexName),
+		rawESClient.Count.WithIndex(e.searchIndices()...),
 		rawESClient.Count.WithContext(ctx),
 		rawESClient.Count.WithBody(queryBody),
 	}
+	if e.routing != "" {
+		opts = append(opts, rawESClient.Count.WithRouting(e.routing))
+	}
+	if e.indexPattern != nil {
+		opts = append(opts,
+			rawESClient.Count.WithIgnoreUnavailable(true),
+			rawESClient.Count.WithAllowNoIndices(true))
+	}
 	res, err := rawESClient.Count(opts...)
 	if err != nil {
 		return 0, err
@@ -517,7 +733,12 @@ func (e es) Query(ctx context.Context, raw interface{}, result interface{}) erro
 	opts := []func(*esapi.SearchRequest){
 		rawESClient.Search.WithBody(body),
 		rawESClient.Search.WithContext(ctx),
-		rawESClient.Search.WithIndex(e.indexName),
+		rawESClient.Search.WithIndex(e.searchIndices()...),
+	}
+	if e.indexPattern != nil {
+		opts = append(opts,
+			rawESClient.Search.WithIgnoreUnavailable(true),
+			rawESClient.Search.WithAllowNoIndices(true))
 	}
 	res, err := rawESClient.Search(opts...)
 	if err != nil {
@@ -549,8 +770,12 @@ func (e es) Save(ctx context.Context, datas ...interface{}) error {
 		rawESClient.Bulk.WithTimeout(20 * time.Second),
 	}
 
+	action, err := e.saveAction(ctx)
+	if err != nil {
+		return err
+	}
+
 	length := len(datas)
-	bulkInsertAction := `{"index": {}}` + "\n"
 
 	for now := 0; now < length; now += BulkItemsLimit {
 		var items []interface{}
@@ -563,7 +788,13 @@ func (e es) Save(ctx context.Context, datas ...interface{}) error {
 		byteBody := &bytes.Buffer{}
 		jd := json.NewEncoder(byteBody)
 		for _, item := range items {
-			byteBody.WriteString(bulkInsertAction)
+			meta, err := e.docMeta("", e.routing, item)
+			if err != nil {
+				return err
+			}
+			if err := jd.Encode(mapStrAny{action: meta}); err != nil {
+				return fmt.Errorf("ges save encode action error. %s", err.Error())
+			}
 			// json encode 会自动加\n
 			if err := jd.Encode(item); err != nil {
 				return fmt.Errorf("ges save encode data error. %s", err.Error())
@@ -591,7 +822,7 @@ func (e es) Save(ctx context.Context, datas ...interface{}) error {
 func ES() Client {
 	return &es{
 		isAgg:     false,
-		sorts:     []string{},
+		sorts:     []interface{}{},
 		fields:    nil,
 		indexName: "",
 		from:      0,
@@ -633,7 +864,7 @@ func (e es) parseSearchRespResult(ctx context.Context, res *esapi.Response, resu
 				return 0, fmt.Errorf("results argument must be initialized")
 			}
 			indexHit := resp.Hits.IndexHits[0]
-			if err := e.parseSearchResultIndexHit(ctx, indexHit.Id, indexHit.Source, resultV.Elem()); err != nil {
+			if err := e.parseSearchHit(ctx, indexHit, resultV.Elem()); err != nil {
 				return 0, err
 			}
 		case reflect.Struct:
@@ -644,7 +875,7 @@ func (e es) parseSearchRespResult(ctx context.Context, res *esapi.Response, resu
 				return 0, fmt.Errorf("results argument must be initialized")
 			}
 			indexHit := resp.Hits.IndexHits[0]
-			if err := e.parseSearchResultIndexHit(ctx, indexHit.Id, indexHit.Source, resultV); err != nil {
+			if err := e.parseSearchHit(ctx, indexHit, resultV); err != nil {
 				return 0, err
 			}
 		default:
@@ -664,7 +895,7 @@ func (e es) parseSearchRespResultArray(ctx context.Context, resp SearchResult, r
 	slice := reflect.MakeSlice(resultV.Elem().Type(), 0, 10)
 	for _, indexHit := range resp.Hits.IndexHits {
 		elem := reflect.New(elemt)
-		err := e.parseSearchResultIndexHit(ctx, indexHit.Id, indexHit.Source, elem)
+		err := e.parseSearchHit(ctx, indexHit, elem)
 		if err != nil {
 			return err
 		}
@@ -692,6 +923,15 @@ func (e es) parseSourceRespResult(ctx context.Context, id string, res *esapi.Res
 	return e.parseSearchResultIndexHit(ctx, id, resBody, resultV)
 }
 
+// parseSearchHit decode source of hit to elemp, fields of hit merged into source
+func (e es) parseSearchHit(ctx context.Context, hit SearchResultHitResult, elemp reflect.Value) error {
+	source, err := mergeHitFields(hit.Source, hit.Fields, elemp.Type())
+	if err != nil {
+		return err
+	}
+	return e.parseSearchResultIndexHit(ctx, hit.Id, source, elemp)
+}
+
 func (e es) parseSearchResultIndexHit(ctx context.Context, esID string, dataRaw json.RawMessage, elemp reflect.Value) error {
 	defer func() {
 		if r := recover(); r != nil {
@@ -0,0 +1,170 @@
+package ges
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		index analysis settings. analyzer, char_filter, tokenizer, token filter, normalizer
+		plugin options(eg: pinyin) not defined in struct put into Params
+
+***************************/
+
+// IndexAnalysis settings.index.analysis, map key is the name used by mapping or other analysis component
+type IndexAnalysis struct {
+	Analyzer   map[string]Analyzer    `json:"analyzer,omitempty"`
+	CharFilter map[string]CharFilter  `json:"char_filter,omitempty"`
+	Tokenizer  map[string]Tokenizer   `json:"tokenizer,omitempty"`
+	Filter     map[string]TokenFilter `json:"filter,omitempty"`
+	Normalizer map[string]Normalizer  `json:"normalizer,omitempty"`
+}
+
+type Analyzer struct {
+	// Type custom or builtin analyzer. eg: standard, cjk
+	Type       string   `json:"type"`
+	Tokenizer  string   `json:"tokenizer,omitempty"`
+	CharFilter []string `json:"char_filter,omitempty"`
+	Filter     []string `json:"filter,omitempty"`
+	Stopwords  []string `json:"stopwords,omitempty"`
+	// PositionIncrementGap gap between values of array field
+	PositionIncrementGap int `json:"position_increment_gap,omitempty"`
+	// Params other options of analyzer. eg: max_token_length, stopwords_path
+	Params map[string]interface{} `json:"-"`
+}
+
+// Normalizer analyzer without tokenizer, used by keyword field
+type Normalizer struct {
+	Type       string   `json:"type"`
+	CharFilter []string `json:"char_filter,omitempty"`
+	Filter     []string `json:"filter,omitempty"`
+}
+
+type CharFilter struct {
+	// Type html_strip, mapping, pattern_replace
+	Type        string   `json:"type"`
+	Mappings    []string `json:"mappings,omitempty"`
+	Pattern     string   `json:"pattern,omitempty"`
+	Replacement string   `json:"replacement,omitempty"`
+	// Params other options of char filter
+	Params map[string]interface{} `json:"-"`
+}
+
+type Tokenizer struct {
+	// Type eg: standard, ngram, pattern, pinyin
+	Type    string `json:"type"`
+	MinGram int    `json:"min_gram,omitempty"`
+	MaxGram int    `json:"max_gram,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+	// Params other options of tokenizer
+	Params map[string]interface{} `json:"-"`
+}
+
+type TokenFilter struct {
+	// Type eg: lowercase, synonym, synonym_graph, stop, pinyin
+	Type string `json:"type"`
+	// Synonyms inline synonym rules. eg: "ipod, i-pod", "universe => cosmos"
+	Synonyms []string `json:"synonyms,omitempty"`
+	// SynonymsPath synonym file path relative to elasticsearch config directory
+	SynonymsPath string   `json:"synonyms_path,omitempty"`
+	Stopwords    []string `json:"stopwords,omitempty"`
+	// Params other options of token filter
+	Params map[string]interface{} `json:"-"`
+}
+
+type analyzerAlias Analyzer
+
+func (a Analyzer) MarshalJSON() ([]byte, error) {
+	return marshalWithParams(analyzerAlias(a), a.Params)
+}
+
+type charFilterAlias CharFilter
+
+func (c CharFilter) MarshalJSON() ([]byte, error) {
+	return marshalWithParams(charFilterAlias(c), c.Params)
+}
+
+type tokenizerAlias Tokenizer
+
+func (t Tokenizer) MarshalJSON() ([]byte, error) {
+	return marshalWithParams(tokenizerAlias(t), t.Params)
+}
+
+type tokenFilterAlias TokenFilter
+
+func (t TokenFilter) MarshalJSON() ([]byte, error) {
+	return marshalWithParams(tokenFilterAlias(t), t.Params)
+}
+
+// marshalWithParams marshal v and merge params into the same json object, field of v has priority
+func marshalWithParams(v interface{}, params map[string]interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil || len(params) == 0 {
+		return raw, err
+	}
+	result := make(map[string]interface{}, len(params))
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, err
+	}
+	for key, val := range params {
+		if _, ok := result[key]; !ok {
+			result[key] = val
+		}
+	}
+	return json.Marshal(result)
+}
+
+func CustomAnalyzer(tokenizer string, charFilters []string, filters ...string) Analyzer {
+	return Analyzer{Type: "custom", Tokenizer: tokenizer, CharFilter: charFilters, Filter: filters}
+}
+
+func CustomNormalizer(charFilters []string, filters ...string) Normalizer {
+	return Normalizer{Type: "custom", CharFilter: charFilters, Filter: filters}
+}
+
+// SynonymFilter synonym_graph token filter with inline rules
+func SynonymFilter(synonyms ...string) TokenFilter {
+	return TokenFilter{Type: "synonym_graph", Synonyms: synonyms}
+}
+
+// SynonymPathFilter synonym_graph token filter with rules file
+func SynonymPathFilter(path string) TokenFilter {
+	return TokenFilter{Type: "synonym_graph", SynonymsPath: path}
+}
+
+type AnalyzeToken struct {
+	Token       string `json:"token"`
+	StartOffset int    `json:"start_offset"`
+	EndOffset   int    `json:"end_offset"`
+	Type        string `json:"type"`
+	Position    int    `json:"position"`
+}
+
+// Analyze tokens of text analyzed by analyzer of index
+func (e esIndex) Analyze(ctx context.Context, analyzer, text string) ([]AnalyzeToken, error) {
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(mapStrAny{"analyzer": analyzer, "text": text}); err != nil {
+		return nil, fmt.Errorf("analyze encode error. %s", err.Error())
+	}
+	res, err := rawESClient.Indices.Analyze(
+		rawESClient.Indices.Analyze.WithIndex(e.name),
+		rawESClient.Indices.Analyze.WithBody(body),
+		rawESClient.Indices.Analyze.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	resp := struct {
+		Tokens []AnalyzeToken `json:"tokens"`
+	}{}
+	if err := parseRespDecode(ctx, res, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tokens, nil
+}
@@ -29,6 +29,20 @@ type bulkItemResp struct {
 	DELETE bulkItemDetailResp `json:"delete"`
 }
 
+// bulkActionMeta metadata line of bulk action. eg: {"index": {"_index": "name"}}
+type bulkActionMeta struct {
+	Index string `json:"_index,omitempty"`
+	Id    string `json:"_id,omitempty"`
+	// Routing child document must route to shard of parent
+	Routing string `json:"routing,omitempty"`
+}
+
+// bulkActionLine metadata line of bulk action without \n
+func bulkActionLine(action string, meta bulkActionMeta) string {
+	line, _ := json.Marshal(mapStrAny{action: meta})
+	return string(line)
+}
+
 type bulkResp struct {
 	Took   int            `json:"took"`
 	Errors bool           `json:"errors"`
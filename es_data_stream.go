@@ -0,0 +1,237 @@
+package ges
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		data stream and composable index template.
+		data stream only accept create op type, Save switch to create op type automatically
+
+***************************/
+
+// IndexTemplate composable index template, DataStream not nil create data stream for matched index name
+type IndexTemplate struct {
+	IndexPatterns []string                 `json:"index_patterns"`
+	DataStream    *IndexTemplateDataStream `json:"data_stream,omitempty"`
+	Template      *IndexMeta               `json:"template,omitempty"`
+	ComposedOf    []string                 `json:"composed_of,omitempty"`
+	Priority      int                      `json:"priority,omitempty"`
+	Version       int64                    `json:"version,omitempty"`
+	Meta          map[string]interface{}   `json:"_meta,omitempty"`
+}
+
+type IndexTemplateDataStream struct {
+	Hidden bool `json:"hidden,omitempty"`
+}
+
+type DataStream struct {
+	Name           string `json:"name"`
+	TimestampField struct {
+		Name string `json:"name"`
+	} `json:"timestamp_field"`
+	Indices []struct {
+		IndexName string `json:"index_name"`
+		IndexUuid string `json:"index_uuid"`
+	} `json:"indices"`
+	Generation int64  `json:"generation"`
+	Status     string `json:"status"`
+	Template   string `json:"template"`
+	IlmPolicy  string `json:"ilm_policy"`
+	Hidden     bool   `json:"hidden"`
+}
+
+// dataStreamMissTTL name not data stream is cached for dataStreamMissTTL, stream may be created later by template
+var dataStreamMissTTL = time.Minute
+
+type dataStreamCacheItem struct {
+	ok bool
+	// expireAt zero never expire, data stream not turn into index
+	expireAt time.Time
+}
+
+// dataStreamCache map[name]dataStreamCacheItem, is index name a data stream
+var dataStreamCache = sync.Map{}
+
+// isDataStream check name is data stream or will be created as data stream by matched index template on first write.
+// positive result cached until DeleteDataStream, negative result cached dataStreamMissTTL, error not cached
+func isDataStream(ctx context.Context, name string) (bool, error) {
+	if name == "" {
+		return false, nil
+	}
+	if v, ok := dataStreamCache.Load(name); ok {
+		item := v.(dataStreamCacheItem)
+		if item.expireAt.IsZero() || time.Now().Before(item.expireAt) {
+			return item.ok, nil
+		}
+	}
+	res, err := rawESClient.Indices.GetDataStream(
+		rawESClient.Indices.GetDataStream.WithName(name),
+		rawESClient.Indices.GetDataStream.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	ok := false
+	switch {
+	case dataStreamUnsupported(res.StatusCode):
+		// elasticsearch before 7.9 not support data stream
+	case res.StatusCode == 404:
+		// data stream not exist yet, created on first write when index template with data_stream matched
+		if ok, err = templateDataStream(ctx, name); err != nil {
+			return false, err
+		}
+	default:
+		resp := struct {
+			DataStreams []DataStream `json:"data_streams"`
+		}{}
+		if err := parseRespDecode(ctx, res, &resp); err != nil {
+			return false, err
+		}
+		ok = len(resp.DataStreams) > 0
+	}
+	item := dataStreamCacheItem{ok: ok}
+	if !ok {
+		item.expireAt = time.Now().Add(dataStreamMissTTL)
+	}
+	dataStreamCache.Store(name, item)
+	return ok, nil
+}
+
+// dataStreamUnsupported status of data stream api not exist, other error status is returned
+func dataStreamUnsupported(status int) bool {
+	return status == 400 || status == 405
+}
+
+// templateDataStream index template matched name create data stream. simulated mapping of data stream template
+// has _data_stream_timestamp
+func templateDataStream(ctx context.Context, name string) (bool, error) {
+	res, err := rawESClient.Indices.SimulateIndexTemplate(name,
+		rawESClient.Indices.SimulateIndexTemplate.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	// no index template matched
+	if res.StatusCode == 404 || dataStreamUnsupported(res.StatusCode) {
+		return false, nil
+	}
+	resp := struct {
+		DataStream *IndexTemplateDataStream `json:"data_stream"`
+		Template   struct {
+			Mappings struct {
+				DataStreamTimestamp *struct {
+					Enabled bool `json:"enabled"`
+				} `json:"_data_stream_timestamp"`
+			} `json:"mappings"`
+		} `json:"template"`
+	}{}
+	if err := parseRespDecode(ctx, res, &resp); err != nil {
+		return false, err
+	}
+	ts := resp.Template.Mappings.DataStreamTimestamp
+	return resp.DataStream != nil || (ts != nil && ts.Enabled), nil
+}
+
+// saveAction bulk action of Save, data stream only accept create op type
+func (e es) saveAction(ctx context.Context) (string, error) {
+	if e.indexPattern != nil {
+		return "index", nil
+	}
+	ds, err := isDataStream(ctx, e.indexName)
+	if err != nil {
+		return "", err
+	}
+	if ds {
+		return "create", nil
+	}
+	return "index", nil
+}
+
+func (e esIndex) PutIndexTemplate(ctx context.Context, name string, template IndexTemplate) error {
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(template); err != nil {
+		return fmt.Errorf("index template encode error. %s", err.Error())
+	}
+	res, err := rawESClient.Indices.PutIndexTemplate(name, body, rawESClient.Indices.PutIndexTemplate.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	return parseRespDecode(ctx, res, nil)
+}
+
+func (e esIndex) DeleteIndexTemplate(ctx context.Context, name string) error {
+	res, err := rawESClient.Indices.DeleteIndexTemplate(name, rawESClient.Indices.DeleteIndexTemplate.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return NotFoundError
+	}
+	return parseRespDecode(ctx, res, nil)
+}
+
+// CreateDataStream create data stream with index name, need a matched index template with data_stream
+func (e esIndex) CreateDataStream(ctx context.Context) error {
+	res, err := rawESClient.Indices.CreateDataStream(e.name, rawESClient.Indices.CreateDataStream.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	if err := parseRespDecode(ctx, res, nil); err != nil {
+		return err
+	}
+	dataStreamCache.Store(e.name, dataStreamCacheItem{ok: true})
+	return nil
+}
+
+func (e esIndex) DeleteDataStream(ctx context.Context) error {
+	res, err := rawESClient.Indices.DeleteDataStream([]string{e.name}, rawESClient.Indices.DeleteDataStream.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	dataStreamCache.Delete(e.name)
+	if res.StatusCode == 404 {
+		return NotFoundError
+	}
+	return parseRespDecode(ctx, res, nil)
+}
+
+// GetDataStream data streams of index name, index name support wildcard
+func (e esIndex) GetDataStream(ctx context.Context) ([]DataStream, error) {
+	res, err := rawESClient.Indices.GetDataStream(
+		rawESClient.Indices.GetDataStream.WithName(e.name),
+		rawESClient.Indices.GetDataStream.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return nil, NotFoundError
+	}
+	resp := struct {
+		DataStreams []DataStream `json:"data_streams"`
+	}{}
+	if err := parseRespDecode(ctx, res, &resp); err != nil {
+		return nil, err
+	}
+	return resp.DataStreams, nil
+}
@@ -0,0 +1,216 @@
+package ges
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		index lifecycle management(ilm) policy api
+
+***************************/
+
+// ILMPolicy index lifecycle policy, phases execute in order hot -> warm -> cold -> delete
+type ILMPolicy struct {
+	Phases ILMPhases              `json:"phases"`
+	Meta   map[string]interface{} `json:"_meta,omitempty"`
+}
+
+type ILMPhases struct {
+	Hot    *ILMPhase `json:"hot,omitempty"`
+	Warm   *ILMPhase `json:"warm,omitempty"`
+	Cold   *ILMPhase `json:"cold,omitempty"`
+	Delete *ILMPhase `json:"delete,omitempty"`
+}
+
+type ILMPhase struct {
+	// MinAge minimum age of the index before entering this phase. eg: 7d, 12h
+	MinAge  string     `json:"min_age,omitempty"`
+	Actions ILMActions `json:"actions"`
+}
+
+type ILMActions struct {
+	Rollover    *RolloverConditions   `json:"rollover,omitempty"`
+	Shrink      *ILMShrinkAction      `json:"shrink,omitempty"`
+	ForceMerge  *ILMForceMergeAction  `json:"forcemerge,omitempty"`
+	SetPriority *ILMSetPriorityAction `json:"set_priority,omitempty"`
+	Delete      *ILMDeleteAction      `json:"delete,omitempty"`
+}
+
+// RolloverConditions rollover when any condition is met
+type RolloverConditions struct {
+	MaxAge              string `json:"max_age,omitempty"`
+	MaxDocs             int64  `json:"max_docs,omitempty"`
+	MaxSize             string `json:"max_size,omitempty"`
+	MaxPrimaryShardSize string `json:"max_primary_shard_size,omitempty"`
+}
+
+type ILMShrinkAction struct {
+	NumberOfShards int `json:"number_of_shards"`
+}
+
+type ILMForceMergeAction struct {
+	MaxNumSegments int `json:"max_num_segments"`
+}
+
+type ILMSetPriorityAction struct {
+	Priority int `json:"priority"`
+}
+
+type ILMDeleteAction struct {
+	DeleteSearchableSnapshot *bool `json:"delete_searchable_snapshot,omitempty"`
+}
+
+// ILMExplain index current lifecycle state
+type ILMExplain struct {
+	Index          string          `json:"index"`
+	Managed        bool            `json:"managed"`
+	Policy         string          `json:"policy"`
+	Phase          string          `json:"phase"`
+	Action         string          `json:"action"`
+	Step           string          `json:"step"`
+	Age            string          `json:"age"`
+	FailedStep     string          `json:"failed_step"`
+	StepInfo       json.RawMessage `json:"step_info,omitempty"`
+	LifecycleDate  int64           `json:"lifecycle_date_millis"`
+	PhaseTime      int64           `json:"phase_time_millis"`
+	ActionTime     int64           `json:"action_time_millis"`
+	StepTime       int64           `json:"step_time_millis"`
+	IsAutoRetrying bool            `json:"is_auto_retryable_error"`
+}
+
+func NewILMPolicy() ILMPolicy {
+	return ILMPolicy{}
+}
+
+func (p ILMPolicy) Hot(phase ILMPhase) ILMPolicy {
+	p.Phases.Hot = &phase
+	return p
+}
+
+func (p ILMPolicy) Warm(phase ILMPhase) ILMPolicy {
+	p.Phases.Warm = &phase
+	return p
+}
+
+func (p ILMPolicy) Cold(phase ILMPhase) ILMPolicy {
+	p.Phases.Cold = &phase
+	return p
+}
+
+// Delete delete the index when index age reach minAge
+func (p ILMPolicy) Delete(minAge string) ILMPolicy {
+	phase := ILMPhaseMinAge(minAge).Delete()
+	p.Phases.Delete = &phase
+	return p
+}
+
+func ILMPhaseMinAge(minAge string) ILMPhase {
+	return ILMPhase{MinAge: minAge}
+}
+
+func (p ILMPhase) Rollover(conditions RolloverConditions) ILMPhase {
+	p.Actions.Rollover = &conditions
+	return p
+}
+
+func (p ILMPhase) Shrink(numberOfShards int) ILMPhase {
+	p.Actions.Shrink = &ILMShrinkAction{NumberOfShards: numberOfShards}
+	return p
+}
+
+func (p ILMPhase) ForceMerge(maxNumSegments int) ILMPhase {
+	p.Actions.ForceMerge = &ILMForceMergeAction{MaxNumSegments: maxNumSegments}
+	return p
+}
+
+func (p ILMPhase) Priority(priority int) ILMPhase {
+	p.Actions.SetPriority = &ILMSetPriorityAction{Priority: priority}
+	return p
+}
+
+func (p ILMPhase) Delete() ILMPhase {
+	p.Actions.Delete = &ILMDeleteAction{}
+	return p
+}
+
+func (e esIndex) PutLifecyclePolicy(ctx context.Context, policy string, p ILMPolicy) error {
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(map[string]ILMPolicy{"policy": p}); err != nil {
+		return fmt.Errorf("ilm policy encode error. %s", err.Error())
+	}
+	res, err := rawESClient.ILM.PutLifecycle(policy,
+		rawESClient.ILM.PutLifecycle.WithBody(body),
+		rawESClient.ILM.PutLifecycle.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	return parseRespDecode(ctx, res, nil)
+}
+
+func (e esIndex) GetLifecyclePolicy(ctx context.Context, policy string) (ILMPolicy, error) {
+	res, err := rawESClient.ILM.GetLifecycle(
+		rawESClient.ILM.GetLifecycle.WithPolicy(policy),
+		rawESClient.ILM.GetLifecycle.WithContext(ctx))
+	if err != nil {
+		return ILMPolicy{}, fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return ILMPolicy{}, NotFoundError
+	}
+	resp := make(map[string]struct {
+		Policy ILMPolicy `json:"policy"`
+	}, 1)
+	if err := parseRespDecode(ctx, res, &resp); err != nil {
+		return ILMPolicy{}, err
+	}
+	item, ok := resp[policy]
+	if !ok {
+		return ILMPolicy{}, NotFoundError
+	}
+	return item.Policy, nil
+}
+
+func (e esIndex) DeleteLifecyclePolicy(ctx context.Context, policy string) error {
+	res, err := rawESClient.ILM.DeleteLifecycle(policy, rawESClient.ILM.DeleteLifecycle.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == 404 {
+		return NotFoundError
+	}
+	return parseRespDecode(ctx, res, nil)
+}
+
+// AttachLifecyclePolicy set index.lifecycle settings of index. rolloverAlias required when policy has rollover action
+func (e esIndex) AttachLifecyclePolicy(ctx context.Context, policy, rolloverAlias string) error {
+	return e.UpdateSettings(ctx, NewIndexDynamicSettings().LifecyclePolicy(policy, rolloverAlias))
+}
+
+// ExplainLifecycle current lifecycle phase/action/step of index, index name support wildcard. map[index name]
+func (e esIndex) ExplainLifecycle(ctx context.Context) (map[string]ILMExplain, error) {
+	res, err := rawESClient.ILM.ExplainLifecycle(e.name, rawESClient.ILM.ExplainLifecycle.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	resp := struct {
+		Indices map[string]ILMExplain `json:"indices"`
+	}{}
+	if err := parseRespDecode(ctx, res, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Indices, nil
+}
@@ -0,0 +1,292 @@
+package ges
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		time based index routing. eg: events-2006.01.02
+		write: index name from document time field
+		read: index names from range condition of time field
+
+***************************/
+
+type IndexInterval string
+
+const (
+	Hourly  IndexInterval = "hourly"
+	Daily   IndexInterval = "daily"
+	Monthly IndexInterval = "monthly"
+	Yearly  IndexInterval = "yearly"
+)
+
+const (
+	// DefaultIndexPatternTimeField document time field used by index pattern when TimeField not set
+	DefaultIndexPatternTimeField = "@timestamp"
+	// maxIndexPatternSearchIndices search range over this number of indices use wildcard index name, avoid too long url
+	maxIndexPatternSearchIndices = 100
+)
+
+// indexPattern immutable after created, clone of es share the same pointer
+type indexPattern struct {
+	prefix   string
+	interval IndexInterval
+	loc      *time.Location
+}
+
+func newIndexPattern(prefix string, interval IndexInterval, tz *time.Location) *indexPattern {
+	if tz == nil {
+		tz = time.UTC
+	}
+	return &indexPattern{prefix: prefix, interval: interval, loc: tz}
+}
+
+func (p *indexPattern) layout() string {
+	switch p.interval {
+	case Hourly:
+		return "2006.01.02.15"
+	case Monthly:
+		return "2006.01"
+	case Yearly:
+		return "2006"
+	default:
+		return "2006.01.02"
+	}
+}
+
+// truncate start time of interval which t belongs to
+func (p *indexPattern) truncate(t time.Time) time.Time {
+	t = t.In(p.loc)
+	switch p.interval {
+	case Hourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, p.loc)
+	case Monthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, p.loc)
+	case Yearly:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, p.loc)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, p.loc)
+	}
+}
+
+func (p *indexPattern) next(t time.Time) time.Time {
+	switch p.interval {
+	case Hourly:
+		return t.Add(time.Hour)
+	case Monthly:
+		return t.AddDate(0, 1, 0)
+	case Yearly:
+		return t.AddDate(1, 0, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+func (p *indexPattern) name(t time.Time) string {
+	return p.prefix + t.In(p.loc).Format(p.layout())
+}
+
+func (p *indexPattern) wildcard() string {
+	return p.prefix + "*"
+}
+
+// names index names cover [from, to], exclusiveTo cover [from, to). too many index return wildcard index name
+func (p *indexPattern) names(from, to time.Time, exclusiveTo bool) []string {
+	if to.Before(from) {
+		return nil
+	}
+	names := make([]string, 0)
+	for t := p.truncate(from); t.Before(to) || (!exclusiveTo && t.Equal(to)); t = p.next(t) {
+		if len(names) >= maxIndexPatternSearchIndices {
+			return []string{p.wildcard()}
+		}
+		names = append(names, p.name(t))
+	}
+	return names
+}
+
+// docIndex index name of document by time field value
+func (p *indexPattern) docIndex(field string, doc interface{}) (string, error) {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("index pattern encode document error. %s", err.Error())
+	}
+	d := json.NewDecoder(bytes.NewReader(raw))
+	d.UseNumber()
+	var value interface{}
+	if err := d.Decode(&value); err != nil {
+		return "", fmt.Errorf("index pattern decode document error. %s", err.Error())
+	}
+	for _, key := range strings.Split(field, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			value = nil
+			break
+		}
+		value = m[key]
+	}
+	if value == nil {
+		return "", fmt.Errorf("index pattern time field %s not found in document", field)
+	}
+	t, ok := parsePatternTime(value)
+	if !ok {
+		return "", fmt.Errorf("index pattern time field %s value %v not support", field, value)
+	}
+	return p.name(t), nil
+}
+
+// searchIndices index names of range condition on time field, unknown range return wildcard index name
+func (p *indexPattern) searchIndices(field string, clauses []interface{}) []string {
+	var from, to time.Time
+	// exclusiveTo upper bound from lt, index start at upper bound not searched
+	exclusiveTo := false
+	for _, clause := range clauses {
+		b, ok := clause.(between)
+		if ptr, isPtr := clause.(*between); isPtr && ptr != nil {
+			b, ok = *ptr, true
+		}
+		if !ok || b.name != field {
+			continue
+		}
+		for _, v := range []interface{}{b.GtePtr, b.GtPtr} {
+			if t, ok := parsePatternTime(v); ok && t.After(from) {
+				from = t
+			}
+		}
+		if t, ok := parsePatternTime(b.LtePtr); ok && (to.IsZero() || t.Before(to)) {
+			to, exclusiveTo = t, false
+		}
+		if t, ok := parsePatternTime(b.LtPtr); ok && (to.IsZero() || !t.After(to)) {
+			to, exclusiveTo = t, true
+		}
+	}
+	if from.IsZero() {
+		return []string{p.wildcard()}
+	}
+	if to.IsZero() {
+		to, exclusiveTo = time.Now(), false
+	}
+	return p.names(from, to, exclusiveTo)
+}
+
+// docMeta bulk action meta of document, index pattern route document to index by time field of data.
+// partial update without time field can not be routed and return error
+func (e es) docMeta(id, routing string, data interface{}) (bulkActionMeta, error) {
+	meta := bulkActionMeta{Id: id, Routing: routing}
+	if e.indexPattern == nil {
+		return meta, nil
+	}
+	indexName, err := e.indexPattern.docIndex(e.patternTimeField(), data)
+	if err != nil {
+		return meta, err
+	}
+	meta.Index = indexName
+	return meta, nil
+}
+
+// patternGetById index of doc unknown without its time, get by ids query over all indices of pattern
+func (e es) patternGetById(ctx context.Context, id string, result interface{}) error {
+	c := es{indexName: e.indexName, indexPattern: e.indexPattern, timeField: e.timeField, routing: e.routing, fields: e.fields}
+	c = c.Where(Ids(id)).Size(1).(es)
+	res, err := c.searchHelper(ctx)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	resp, err := parseSearchRespDefaultDecode(ctx, res)
+	if err != nil {
+		return err
+	}
+	if len(resp.Hits.IndexHits) == 0 {
+		return NotFoundError
+	}
+	hit := resp.Hits.IndexHits[0]
+	return c.parseSearchHit(ctx, hit, reflect.ValueOf(result))
+}
+
+var patternTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parsePatternTime time.Time, epoch_millis number or date string. date math not support
+func parsePatternTime(v interface{}) (time.Time, bool) {
+	switch val := v.(type) {
+	case time.Time:
+		return val, !val.IsZero()
+	case *time.Time:
+		if val == nil {
+			return time.Time{}, false
+		}
+		return *val, !val.IsZero()
+	case int:
+		return time.UnixMilli(int64(val)), true
+	case int64:
+		return time.UnixMilli(val), true
+	case uint64:
+		return time.UnixMilli(int64(val)), true
+	case float64:
+		return time.UnixMilli(int64(val)), true
+	case json.Number:
+		i64, err := val.Int64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		return time.UnixMilli(i64), true
+	case string:
+		if i64, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return time.UnixMilli(i64), true
+		}
+		for _, layout := range patternTimeLayouts {
+			if t, err := time.Parse(layout, val); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// RolloverResult response of rollover api
+type RolloverResult struct {
+	Acknowledged       bool            `json:"acknowledged"`
+	ShardsAcknowledged bool            `json:"shards_acknowledged"`
+	OldIndex           string          `json:"old_index"`
+	NewIndex           string          `json:"new_index"`
+	RolledOver         bool            `json:"rolled_over"`
+	DryRun             bool            `json:"dry_run"`
+	Conditions         map[string]bool `json:"conditions"`
+}
+
+// Rollover create new index for alias when any of conditions is met, empty conditions rollover unconditionally
+func (e es) Rollover(ctx context.Context, alias string, conditions RolloverConditions) (RolloverResult, error) {
+	var result RolloverResult
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(map[string]RolloverConditions{"conditions": conditions}); err != nil {
+		return result, fmt.Errorf("rollover conditions encode error. %s", err.Error())
+	}
+	res, err := rawESClient.Indices.Rollover(alias,
+		rawESClient.Indices.Rollover.WithBody(body),
+		rawESClient.Indices.Rollover.WithContext(ctx))
+	if err != nil {
+		return result, fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	if err := parseRespDecode(ctx, res, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
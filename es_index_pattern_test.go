@@ -0,0 +1,86 @@
+package ges
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+
+***************************/
+
+func TestIndexPatternDocIndex(t *testing.T) {
+	tz := time.FixedZone("CST", 8*3600)
+	p := newIndexPattern("events-", Daily, tz)
+
+	name, err := p.docIndex("ts", mapStrAny{"ts": "2024-05-01T20:00:00Z"})
+	require.NoError(t, err, "TestIndexPatternDocIndex string time")
+	require.Equal(t, "events-2024.05.02", name, "TestIndexPatternDocIndex string time")
+
+	name, err = p.docIndex("meta.ts", mapStrAny{"meta": mapStrAny{"ts": int64(1714521600000)}})
+	require.NoError(t, err, "TestIndexPatternDocIndex epoch millis")
+	require.Equal(t, "events-2024.05.01", name, "TestIndexPatternDocIndex epoch millis")
+
+	_, err = p.docIndex("ts", mapStrAny{"label": "no time"})
+	require.Error(t, err, "TestIndexPatternDocIndex missing time field")
+}
+
+func TestIndexPatternSearchIndices(t *testing.T) {
+	client := ES().IndexPattern("events-", Daily, time.UTC).TimeField("ts").
+		Where(Term("label", "a"), Range("ts", "2024-05-01T10:00:00Z", "2024-05-03T00:00:00Z")).(es)
+	require.Equal(t, []string{"events-2024.05.01", "events-2024.05.02", "events-2024.05.03"}, client.searchIndices(), "TestIndexPatternSearchIndices")
+
+	client = ES().IndexPattern("events-", Monthly, time.UTC).TimeField("ts").
+		Where(Gte("ts", time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC).UnixMilli())).
+		Where(Lt("ts", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).UnixMilli())).(es)
+	require.Equal(t, []string{"events-2024.01", "events-2024.02"}, client.searchIndices(), "TestIndexPatternSearchIndices monthly lt exclusive")
+
+	client = ES().IndexPattern("events-", Monthly, time.UTC).TimeField("ts").
+		Where(Gte("ts", "2024-01-15"), Lte("ts", "2024-03-01")).(es)
+	require.Equal(t, []string{"events-2024.01", "events-2024.02", "events-2024.03"}, client.searchIndices(), "TestIndexPatternSearchIndices monthly lte inclusive")
+
+	client = ES().IndexPattern("events-", Daily, time.UTC).Where(Term("label", "a")).(es)
+	require.Equal(t, []string{"events-*"}, client.searchIndices(), "TestIndexPatternSearchIndices without range")
+}
+
+func TestIndexPatternDocMeta(t *testing.T) {
+	client := ES().IndexPattern("events-", Daily, time.UTC).TimeField("ts").(es)
+	meta, err := client.docMeta("1", "r1", mapStrAny{"ts": "2024-05-01T20:00:00Z", "label": "a"})
+	require.NoError(t, err, "TestIndexPatternDocMeta")
+	require.Equal(t, bulkActionMeta{Index: "events-2024.05.01", Id: "1", Routing: "r1"}, meta, "TestIndexPatternDocMeta")
+
+	_, err = client.docMeta("1", "", mapStrAny{"label": "partial update"})
+	require.Error(t, err, "TestIndexPatternDocMeta update without time field")
+
+	meta, err = ES().IndexName("events").(es).docMeta("1", "", mapStrAny{"label": "a"})
+	require.NoError(t, err, "TestIndexPatternDocMeta without pattern")
+	require.Equal(t, bulkActionMeta{Id: "1"}, meta, "TestIndexPatternDocMeta without pattern")
+}
+
+func TestIndexPatternGetByIdAndQuery(t *testing.T) {
+	var paths []string
+	restore := mockESServer(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(`{"hits":{"total":{"value":1,"relation":"eq"},"hits":[` +
+			`{"_index":"events-2024.05.01","_id":"1","_source":{"label":"a"}}]}}`))
+	})
+	defer restore()
+
+	client := ES().IndexPattern("events-", Daily, time.UTC).TimeField("ts")
+	result := mapStrAny{}
+	require.NoError(t, client.GetById(context.Background(), "1", &result), "TestIndexPatternGetByIdAndQuery GetById")
+	require.Equal(t, "a", result["label"], "TestIndexPatternGetByIdAndQuery GetById")
+
+	raw := mapStrAny{}
+	err := client.Where(Gte("ts", "2024-05-01T00:00:00Z"), Lt("ts", "2024-05-02T00:00:00Z")).(es).
+		Query(context.Background(), mapStrAny{"query": mapStrAny{"match_all": mapStrAny{}}}, &raw)
+	require.NoError(t, err, "TestIndexPatternGetByIdAndQuery Query")
+	require.Equal(t, []string{"/events-*/_search", "/events-2024.05.01/_search"}, paths, "TestIndexPatternGetByIdAndQuery paths")
+}
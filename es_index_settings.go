@@ -0,0 +1,161 @@
+package ges
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		dynamic index settings, can be changed on an open index
+
+***************************/
+
+// IndexDynamicSettings nil field keep current value
+type IndexDynamicSettings struct {
+	NumberOfReplicas   *int                    `json:"number_of_replicas,omitempty"`
+	RefreshInterval    *string                 `json:"refresh_interval,omitempty"`
+	MaxResultWindow    *int                    `json:"max_result_window,omitempty"`
+	TranslogDurability *string                 `json:"translog.durability,omitempty"`
+	Lifecycle          *IndexLifecycleSettings `json:"lifecycle,omitempty"`
+}
+
+func NewIndexDynamicSettings() IndexDynamicSettings {
+	return IndexDynamicSettings{}
+}
+
+func (s IndexDynamicSettings) Replicas(n int) IndexDynamicSettings {
+	s.NumberOfReplicas = &n
+	return s
+}
+
+// Refresh refresh interval. eg: 1s, -1 disable refresh
+func (s IndexDynamicSettings) Refresh(interval string) IndexDynamicSettings {
+	s.RefreshInterval = &interval
+	return s
+}
+
+func (s IndexDynamicSettings) ResultWindow(n int) IndexDynamicSettings {
+	s.MaxResultWindow = &n
+	return s
+}
+
+// Durability translog durability. request or async
+func (s IndexDynamicSettings) Durability(durability string) IndexDynamicSettings {
+	s.TranslogDurability = &durability
+	return s
+}
+
+func (s IndexDynamicSettings) LifecyclePolicy(policy, rolloverAlias string) IndexDynamicSettings {
+	s.Lifecycle = &IndexLifecycleSettings{Name: policy, RolloverAlias: rolloverAlias}
+	return s
+}
+
+func (e esIndex) UpdateSettings(ctx context.Context, settings IndexDynamicSettings) error {
+	return e.putSettings(ctx, e.name, map[string]IndexDynamicSettings{"index": settings})
+}
+
+// putSettings body is settings json object, nil value of key reset setting to default
+func (e esIndex) putSettings(ctx context.Context, name string, settings interface{}) error {
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(settings); err != nil {
+		return fmt.Errorf("index settings encode error. %s", err.Error())
+	}
+	res, err := rawESClient.Indices.PutSettings(body,
+		rawESClient.Indices.PutSettings.WithIndex(name),
+		rawESClient.Indices.PutSettings.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	return parseRespDecode(ctx, res, nil)
+}
+
+// flatSettings map[index name]map[flat setting key]value. eg: index.refresh_interval
+func (e esIndex) flatSettings(ctx context.Context) (map[string]map[string]interface{}, error) {
+	res, err := rawESClient.Indices.GetSettings(
+		rawESClient.Indices.GetSettings.WithIndex(e.name),
+		rawESClient.Indices.GetSettings.WithFlatSettings(true),
+		rawESClient.Indices.GetSettings.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	resp := make(map[string]struct {
+		Settings map[string]interface{} `json:"settings"`
+	})
+	if err := parseRespDecode(ctx, res, &resp); err != nil {
+		return nil, err
+	}
+	result := make(map[string]map[string]interface{}, len(resp))
+	for name, item := range resp {
+		result[name] = item.Settings
+	}
+	return result, nil
+}
+
+func (e esIndex) Refresh(ctx context.Context) error {
+	res, err := rawESClient.Indices.Refresh(
+		rawESClient.Indices.Refresh.WithIndex(e.name),
+		rawESClient.Indices.Refresh.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	return parseRespDecode(ctx, res, nil)
+}
+
+// bulkLoadSettingKeys settings changed by BulkLoadMode
+var bulkLoadSettingKeys = []string{"index.refresh_interval", "index.number_of_replicas"}
+
+// BulkLoadMode disable refresh and replicas, run fn, then restore settings and refresh index.
+// settings restored even fn return error or ctx is done
+func (e esIndex) BulkLoadMode(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	saved, err := e.flatSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("bulk load mode get settings error. %s", err.Error())
+	}
+	if err := e.UpdateSettings(ctx, NewIndexDynamicSettings().Refresh("-1").Replicas(0)); err != nil {
+		return fmt.Errorf("bulk load mode update settings error. %s", err.Error())
+	}
+
+	defer func() {
+		// ctx may be canceled by fn, restore settings with new context
+		restoreCtx := context.Background()
+		restoreErr := e.restoreSettings(restoreCtx, saved)
+		if restoreErr == nil {
+			restoreErr = e.Refresh(restoreCtx)
+		}
+		if restoreErr == nil {
+			return
+		}
+		if err != nil {
+			err = fmt.Errorf("%s, restore settings error. %s", err.Error(), restoreErr.Error())
+			return
+		}
+		err = fmt.Errorf("bulk load mode restore settings error. %s", restoreErr.Error())
+	}()
+
+	return fn(ctx)
+}
+
+func (e esIndex) restoreSettings(ctx context.Context, saved map[string]map[string]interface{}) error {
+	for name, settings := range saved {
+		// key not exist means default value, set null reset to default
+		body := make(map[string]interface{}, len(bulkLoadSettingKeys))
+		for _, key := range bulkLoadSettingKeys {
+			body[key] = settings[key]
+		}
+		if err := e.putSettings(ctx, name, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
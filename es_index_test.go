@@ -0,0 +1,209 @@
+package ges
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/stretchr/testify/require"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+
+***************************/
+
+func TestILMPolicy(t *testing.T) {
+	policy := NewILMPolicy().
+		Hot(ILMPhaseMinAge("0ms").Rollover(RolloverConditions{MaxAge: "1d", MaxPrimaryShardSize: "50gb"})).
+		Warm(ILMPhaseMinAge("7d").Shrink(1).ForceMerge(1)).
+		Delete("30d")
+	actual, err := json.Marshal(policy)
+	require.NoError(t, err, "TestILMPolicy json.Marshal")
+	expected := `{"phases":{"hot":{"min_age":"0ms","actions":{"rollover":{"max_age":"1d","max_primary_shard_size":"50gb"}}},"warm":{"min_age":"7d","actions":{"shrink":{"number_of_shards":1},"forcemerge":{"max_num_segments":1}}},"delete":{"min_age":"30d","actions":{"delete":{}}}}}`
+	require.Equal(t, expected, string(actual), "TestILMPolicy ")
+}
+
+func TestIndexAnalysis(t *testing.T) {
+	analysis := IndexAnalysis{
+		Tokenizer: map[string]Tokenizer{"py": {Type: "pinyin", Params: map[string]interface{}{"keep_first_letter": true, "type": "ignored"}}},
+		Filter:    map[string]TokenFilter{"syn": SynonymFilter("ipod, i-pod")},
+		Analyzer: map[string]Analyzer{
+			"name_py": CustomAnalyzer("py", nil, "lowercase", "syn"),
+			"std":     {Type: "standard", Stopwords: []string{"a"}, Params: map[string]interface{}{"max_token_length": 5}},
+		},
+	}
+	actual, err := json.Marshal(analysis)
+	require.NoError(t, err, "TestIndexAnalysis json.Marshal")
+	expected := `{"analyzer":{"name_py":{"type":"custom","tokenizer":"py","filter":["lowercase","syn"]},"std":{"max_token_length":5,"stopwords":["a"],"type":"standard"}},"tokenizer":{"py":{"keep_first_letter":true,"type":"pinyin"}},"filter":{"syn":{"type":"synonym_graph","synonyms":["ipod, i-pod"]}}}`
+	require.Equal(t, expected, string(actual), "TestIndexAnalysis ")
+}
+
+func TestIndexDynamicSettings(t *testing.T) {
+	settings := NewIndexDynamicSettings().Refresh("-1").Replicas(0)
+	actual, err := json.Marshal(map[string]IndexDynamicSettings{"index": settings})
+	require.NoError(t, err, "TestIndexDynamicSettings json.Marshal")
+	expected := `{"index":{"number_of_replicas":0,"refresh_interval":"-1"}}`
+	require.Equal(t, expected, string(actual), "TestIndexDynamicSettings ")
+}
+
+func TestBulkLoadMode(t *testing.T) {
+	var requests []string
+	restoreStatus := http.StatusOK
+	restore := mockESServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+string(body)))
+		switch {
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"order":{"settings":{"index.refresh_interval":"5s","index.number_of_shards":"1"}}}`))
+		case r.Method == http.MethodPut && strings.Contains(string(body), `"5s"`):
+			w.WriteHeader(restoreStatus)
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		default:
+			_, _ = w.Write([]byte(`{"acknowledged":true}`))
+		}
+	})
+	defer restore()
+
+	loadErr := errors.New("load fail")
+	err := ES().IndexName("order").Index().BulkLoadMode(context.Background(), func(ctx context.Context) error {
+		return loadErr
+	})
+	require.ErrorIs(t, err, loadErr, "TestBulkLoadMode fn error")
+	expected := []string{
+		"GET /order/_settings",
+		`PUT /order/_settings {"index":{"number_of_replicas":0,"refresh_interval":"-1"}}`,
+		`PUT /order/_settings {"index.number_of_replicas":null,"index.refresh_interval":"5s"}`,
+		"POST /order/_refresh",
+	}
+	require.Equal(t, expected, requests, "TestBulkLoadMode restore after fn error")
+
+	// fn error and restore error both returned
+	requests, restoreStatus = nil, http.StatusInternalServerError
+	err = ES().IndexName("order").Index().BulkLoadMode(context.Background(), func(ctx context.Context) error {
+		return loadErr
+	})
+	require.Error(t, err, "TestBulkLoadMode restore error")
+	require.Contains(t, err.Error(), "load fail", "TestBulkLoadMode restore error")
+	require.Contains(t, err.Error(), "restore settings error", "TestBulkLoadMode restore error")
+	require.Equal(t, expected[:3], requests, "TestBulkLoadMode restore error")
+}
+
+// mockESServer rawESClient request to handler, return function restore rawESClient
+func mockESServer(t *testing.T, handler http.HandlerFunc) func() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/" {
+			_, _ = w.Write([]byte(`{"version":{"number":"7.17.0"},"tagline":"You Know, for Search"}`))
+			return
+		}
+		handler(w, r)
+	}))
+	c, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{srv.URL}})
+	require.NoError(t, err, "mockESServer elasticsearch.NewClient")
+	raw := rawESClient
+	rawESClient = c
+	return func() {
+		rawESClient = raw
+		srv.Close()
+	}
+}
+
+func TestDataStreamSaveAction(t *testing.T) {
+	var requests int32
+	status := map[string]int{"/_data_stream/flaky": http.StatusServiceUnavailable}
+	restore := mockESServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if code, ok := status[r.URL.Path]; ok {
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(`{"error":"unavailable"}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/_data_stream/logs-app":
+			_, _ = w.Write([]byte(`{"data_streams":[{"name":"logs-app"}]}`))
+		case "/_index_template/_simulate_index/logs-new":
+			_, _ = w.Write([]byte(`{"template":{"mappings":{"_data_stream_timestamp":{"enabled":true}}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+		}
+	})
+	defer restore()
+	defer func(ttl time.Duration) { dataStreamMissTTL = ttl }(dataStreamMissTTL)
+	for _, name := range []string{"logs-app", "logs-new", "plain", "flaky"} {
+		dataStreamCache.Delete(name)
+	}
+
+	for name, expected := range map[string]string{"logs-app": "create", "logs-new": "create", "plain": "index"} {
+		action, err := ES().IndexName(name).(es).saveAction(context.Background())
+		require.NoError(t, err, "TestDataStreamSaveAction "+name)
+		require.Equal(t, expected, action, "TestDataStreamSaveAction "+name)
+	}
+	action, err := ES().IndexPattern("logs-", Daily, time.UTC).(es).saveAction(context.Background())
+	require.NoError(t, err, "TestDataStreamSaveAction index pattern")
+	require.Equal(t, "index", action, "TestDataStreamSaveAction index pattern")
+
+	// transient error returned and not cached
+	_, err = isDataStream(context.Background(), "flaky")
+	require.Error(t, err, "TestDataStreamSaveAction flaky")
+	_, cached := dataStreamCache.Load("flaky")
+	require.False(t, cached, "TestDataStreamSaveAction error not cached")
+
+	// positive and negative result cached
+	atomic.StoreInt32(&requests, 0)
+	for _, name := range []string{"logs-app", "logs-new", "plain"} {
+		_, err := isDataStream(context.Background(), name)
+		require.NoError(t, err, "TestDataStreamSaveAction cached "+name)
+	}
+	require.Equal(t, int32(0), atomic.LoadInt32(&requests), "TestDataStreamSaveAction cached")
+
+	// negative result expired, stream created by template later
+	dataStreamMissTTL = 0
+	dataStreamCache.Delete("plain")
+	_, err = isDataStream(context.Background(), "plain")
+	require.NoError(t, err, "TestDataStreamSaveAction expired")
+	_, err = isDataStream(context.Background(), "plain")
+	require.NoError(t, err, "TestDataStreamSaveAction expired")
+	require.Equal(t, int32(4), atomic.LoadInt32(&requests), "TestDataStreamSaveAction negative cache expired")
+}
+
+func TestRuntimeFieldCountAndDelete(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	restore := mockESServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path + "?" + r.URL.RawQuery
+		body = nil
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"took":1,"hits":{"total":{"value":3,"relation":"eq"},"hits":[]}}`))
+	})
+	defer restore()
+
+	client := ES().IndexName("product").
+		RuntimeField("discount_price", MappingTypeDouble, NewScript("emit(doc['price'].value * 0.9)", nil)).
+		Where(Gt("discount_price", 10))
+	cnt, err := client.Count(context.Background())
+	require.NoError(t, err, "TestRuntimeFieldCountAndDelete Count")
+	require.Equal(t, uint64(3), cnt, "TestRuntimeFieldCountAndDelete Count")
+	require.Contains(t, path, "/product/_search", "TestRuntimeFieldCountAndDelete Count path")
+	require.Contains(t, path, "size=0", "TestRuntimeFieldCountAndDelete Count size")
+	require.Contains(t, path, "track_total_hits=true", "TestRuntimeFieldCountAndDelete Count track_total_hits")
+	require.Contains(t, body, "runtime_mappings", "TestRuntimeFieldCountAndDelete Count runtime_mappings")
+	require.Contains(t, body, "query", "TestRuntimeFieldCountAndDelete Count query")
+
+	path = ""
+	err = client.Delete(context.Background())
+	require.Error(t, err, "TestRuntimeFieldCountAndDelete Delete")
+	require.Empty(t, path, "TestRuntimeFieldCountAndDelete Delete not requested")
+}
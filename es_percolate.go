@@ -0,0 +1,82 @@
+package ges
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		percolator. store query as document, match document against stored queries
+
+***************************/
+
+// percolateMaxHits max matched queries of Percolate without Size, default index.max_result_window
+const percolateMaxHits = 10000
+
+// Percolate stored queries in percolator field match any of docs
+func (f filter) Percolate(field string, docs ...interface{}) Filter {
+	value := map[string]interface{}{"field": field}
+	if len(docs) == 1 {
+		value["document"] = docs[0]
+	} else {
+		value["documents"] = docs
+	}
+	f.condition = append(f.condition, map[string]interface{}{"percolate": value})
+	return f
+}
+
+// RegisterQuery store query to percolator field of document id, empty query match all document
+func (e es) RegisterQuery(ctx context.Context, field, id string, query Filter) error {
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(map[string]interface{}{field: singleQuery(query)}); err != nil {
+		return fmt.Errorf("percolator query encode error. %s", err.Error())
+	}
+	res, err := rawESClient.Index(e.indexName, body,
+		rawESClient.Index.WithContext(ctx),
+		rawESClient.Index.WithDocumentID(id),
+		rawESClient.Index.WithRouting(e.routing),
+		rawESClient.Index.WithRefresh("true"))
+	if err != nil {
+		return fmt.Errorf("percolator query save error. %s", err.Error())
+	}
+	defer res.Body.Close()
+	return parseRespDecode(ctx, res, nil)
+}
+
+// Percolate id of stored queries match any of docs, other conditions of client filter stored query documents.
+// Size limit number of ids, without Size more than 10000 matched queries is error
+func (e es) Percolate(ctx context.Context, field string, docs ...interface{}) ([]string, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	client := e.Filter(filter{}.Percolate(field, docs...)).(es)
+	if client.size == 0 {
+		client.size = percolateMaxHits
+	}
+	// only _id used, stored query not fetched
+	res, err := client.searchHelper(ctx, rawESClient.Search.WithSource("false"))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	resp, err := parseSearchRespDefaultDecode(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+	hits := resp.Hits.IndexHits
+	if e.size == 0 && resp.Hits.Total.Value > uint64(len(hits)) {
+		return nil, fmt.Errorf("percolate error. %d queries matched, more than %d, set Size or narrow by filter",
+			resp.Hits.Total.Value, percolateMaxHits)
+	}
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.Id)
+	}
+	return ids, nil
+}
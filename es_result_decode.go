@@ -1,10 +1,12 @@
 package ges
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
+	"reflect"
 )
 
 /***************************
@@ -58,3 +60,41 @@ func parseSearchRespIndexDecode(ctx context.Context, res *esapi.Response) (map[s
 
 	return resp, nil
 }
+
+// parseRespDecode check response status and decode response body to result, result nil only check status
+func parseRespDecode(ctx context.Context, res *esapi.Response, result interface{}) error {
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch response error. status: %v, message: %s", res.Status(), res.String())
+	}
+	if result == nil {
+		return nil
+	}
+
+	d := json.NewDecoder(res.Body)
+	d.UseNumber()
+	if err := d.Decode(result); err != nil {
+		return fmt.Errorf("error parsing the response body: %s", err)
+	}
+	return nil
+}
+
+// Decode inner hits named name to result, result must be slice address. name not exist result is empty
+func (h HitInnerHits) Decode(name string, result interface{}) error {
+	resultV := reflect.ValueOf(result)
+	if resultV.Kind() != reflect.Ptr || resultV.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("results argument must be a slice address")
+	}
+	hits := h[name].Hits.IndexHits
+	slice := reflect.MakeSlice(resultV.Elem().Type(), 0, len(hits))
+	for _, hit := range hits {
+		elem := reflect.New(resultV.Elem().Type().Elem())
+		d := json.NewDecoder(bytes.NewReader(hit.Source))
+		d.UseNumber()
+		if err := d.Decode(elem.Interface()); err != nil {
+			return fmt.Errorf("inner hits %s decode error. %s", name, err.Error())
+		}
+		slice = reflect.Append(slice, elem.Elem())
+	}
+	resultV.Elem().Set(slice)
+	return nil
+}
@@ -0,0 +1,132 @@
+package ges
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		script query and search time runtime fields
+
+***************************/
+
+// RuntimeField field computed by script at search time, script emit value. eg: emit(doc['price'].value * 0.9)
+type RuntimeField struct {
+	Type   MappingType `json:"type"`
+	Script *Script     `json:"script,omitempty"`
+}
+
+// RuntimeField add runtime_mappings to search body, field usable by Where, OrderBy, Agg and Fields like mapping field.
+// value of runtime field in Fields is merged into source of hit
+func (e es) RuntimeField(name string, fieldType MappingType, script Script) Client {
+	e = e.Clone()
+	runtimeMappings := make(map[string]RuntimeField, len(e.runtimeMappings)+1)
+	for key, val := range e.runtimeMappings {
+		runtimeMappings[key] = val
+	}
+	runtimeMappings[name] = RuntimeField{Type: fieldType, Script: &script}
+	e.runtimeMappings = runtimeMappings
+	return e
+}
+
+// runtimeFields runtime fields in Fields, runtime field not in _source, must request by fields of body
+func (e es) runtimeFields() []string {
+	var fields []string
+	for _, field := range e.fields {
+		if _, ok := e.runtimeMappings[field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// countBySearch count api not support runtime_mappings, count by search with size 0 and track_total_hits
+func (e es) countBySearch(ctx context.Context) (uint64, error) {
+	// only total needed
+	e.isAgg, e.agg, e.sorts, e.fields = true, nil, nil, nil
+	res, err := e.searchHelper(ctx, rawESClient.Search.WithTrackTotalHits(true))
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	resp, err := parseSearchRespDefaultDecode(ctx, res)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Hits.Total.Value, nil
+}
+
+// mergeHitFields set fields of hit to source, shape of value decided by destination type.
+// struct field not slice get single value, multiple values is error. slice field, map and other destination get array
+func mergeHitFields(source json.RawMessage, fields map[string]json.RawMessage, dest reflect.Type) (json.RawMessage, error) {
+	if len(fields) == 0 {
+		return source, nil
+	}
+	doc := make(map[string]json.RawMessage, len(fields))
+	if len(source) != 0 && string(source) != "null" {
+		if err := json.Unmarshal(source, &doc); err != nil {
+			return nil, fmt.Errorf("hit source decode error. %s", err.Error())
+		}
+	}
+	for dest != nil && dest.Kind() == reflect.Ptr {
+		dest = dest.Elem()
+	}
+	for name, raw := range fields {
+		fieldType, ok := jsonFieldType(dest, name)
+		if !ok {
+			doc[name] = raw
+			continue
+		}
+		values := make([]json.RawMessage, 0, 1)
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return nil, fmt.Errorf("hit field %s decode error. %s", name, err.Error())
+		}
+		switch len(values) {
+		case 0:
+		case 1:
+			doc[name] = values[0]
+		default:
+			return nil, fmt.Errorf("hit field %s has %d values, struct field type %s not slice", name, len(values), fieldType)
+		}
+	}
+	return json.Marshal(doc)
+}
+
+// jsonFieldType type of struct field decoded from json key name, ok false if dest not struct, field not found or slice
+func jsonFieldType(dest reflect.Type, name string) (reflect.Type, bool) {
+	if dest == nil || dest.Kind() != reflect.Struct {
+		return nil, false
+	}
+	for i := 0; i < dest.NumField(); i++ {
+		field := dest.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" || (tag != name && (tag != "" || !strings.EqualFold(field.Name, name))) {
+			continue
+		}
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType == reflect.TypeOf(json.RawMessage{}) || fieldType.Kind() == reflect.Interface {
+			return nil, false
+		}
+		if fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array {
+			return nil, false
+		}
+		return fieldType, true
+	}
+	return nil, false
+}
+
+// Script documents which script return true. eg: doc['price'].value > params.min
+func (f filter) Script(script Script) Filter {
+	f.condition = append(f.condition, map[string]interface{}{"script": map[string]interface{}{"script": script}})
+	return f
+}
@@ -0,0 +1,189 @@
+package ges
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		oversized terms values rewrite. split into should clauses or terms lookup document
+
+***************************/
+
+// DefaultTermsLimit default index.max_terms_count of elasticsearch
+const DefaultTermsLimit = 65536
+
+// termsLookupPath field of terms lookup document contains values
+const termsLookupPath = "values"
+
+// TermsStrategy rewrite terms which values more than Limit before request.
+// terms in where, filter, or, not, bool and nested query of them are rewritten, terms in other compound query
+// (eg: constant_score, function_score, has_child) are not rewritten
+type TermsStrategy struct {
+	// Limit max values of one terms clause, 0 not rewrite
+	Limit int
+	// LookupIndex values saved to document of LookupIndex and query by terms lookup, empty split into should clauses.
+	// values fetched by terms lookup still limited by index.max_terms_count, only request body is small
+	LookupIndex string
+}
+
+// TermsStrategy rewrite oversized terms clause, avoid exceeding index.max_terms_count
+func (e es) TermsStrategy(strategy TermsStrategy) Client {
+	e = e.Clone()
+	e.termsStrategy = strategy
+	return e
+}
+
+// termsLookupSaved lookup documents saved by this process, key is index/id
+var termsLookupSaved sync.Map
+
+type termsRewriter struct {
+	strategy TermsStrategy
+	// docs lookup documents to save, key is document id
+	docs map[string][]byte
+	err  error
+}
+
+// rewriteTerms copy of e with oversized terms rewritten, lookup documents saved before return
+func (e es) rewriteTerms(ctx context.Context) (es, error) {
+	if e.termsStrategy.Limit <= 0 {
+		return e, nil
+	}
+	r := &termsRewriter{strategy: e.termsStrategy}
+	e.cond = cond{
+		must:   r.items(e.cond.must),
+		filter: r.items(e.cond.filter),
+		should: r.items(e.cond.should),
+		not:    r.items(e.cond.not),
+	}
+	if r.err != nil {
+		return e, r.err
+	}
+	return e, r.saveLookupDocs(ctx)
+}
+
+func (r *termsRewriter) items(items []interface{}) []interface{} {
+	if len(items) == 0 {
+		return items
+	}
+	result := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		result = append(result, r.item(item))
+	}
+	return result
+}
+
+func (r *termsRewriter) bool(b esQueryBool) esQueryBool {
+	b.Must = r.items(b.Must)
+	b.Filter = r.items(b.Filter)
+	b.Should = r.items(b.Should)
+	b.Not = r.items(b.Not)
+	return b
+}
+
+func (r *termsRewriter) item(item interface{}) interface{} {
+	// Clone copy condition items as pointer
+	switch val := item.(type) {
+	case *terms:
+		return r.item(*val)
+	case *boolFilter:
+		return r.item(*val)
+	case *esNested:
+		return r.item(*val)
+	case terms:
+		values := reflect.ValueOf(val.values)
+		if (values.Kind() != reflect.Slice && values.Kind() != reflect.Array) || values.Len() <= r.strategy.Limit {
+			return item
+		}
+		if r.strategy.LookupIndex != "" {
+			return r.lookup(val.name, val.values)
+		}
+		return splitTerms(val.name, values, r.strategy.Limit)
+	case boolFilter:
+		return boolFilter{Bool: r.bool(val.Bool)}
+	case esNested:
+		val.Query.Bool = r.bool(val.Query.Bool)
+		return val
+	case map[string]interface{}:
+		// nested query. eg: {"nested": esNested}
+		nested, ok := val["nested"]
+		if !ok || len(val) != 1 {
+			return item
+		}
+		return map[string]interface{}{"nested": r.item(nested)}
+	default:
+		return item
+	}
+}
+
+// lookup terms lookup of document contains values, id is hash of values, same values share document
+func (r *termsRewriter) lookup(field string, values interface{}) interface{} {
+	body, err := json.Marshal(map[string]interface{}{termsLookupPath: values})
+	if err != nil {
+		r.err = fmt.Errorf("terms lookup document encode error. %s", err.Error())
+		return terms{name: field, values: values}
+	}
+	hash := sha1.Sum(body)
+	id := hex.EncodeToString(hash[:])
+	if r.docs == nil {
+		r.docs = make(map[string][]byte)
+	}
+	r.docs[id] = body
+	return terms{name: field, values: TermsLookupValue{Index: r.strategy.LookupIndex, Id: id, Path: termsLookupPath}}
+}
+
+// saveLookupDocs create lookup documents not saved yet. terms lookup fetch document by realtime get, refresh not needed.
+// document already exists is same values, conflict is ignored
+func (r *termsRewriter) saveLookupDocs(ctx context.Context) error {
+	ids := make([]string, 0, len(r.docs))
+	for id := range r.docs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		key := r.strategy.LookupIndex + "/" + id
+		if _, ok := termsLookupSaved.Load(key); ok {
+			continue
+		}
+		res, err := rawESClient.Index(r.strategy.LookupIndex, bytes.NewReader(r.docs[id]),
+			rawESClient.Index.WithContext(ctx),
+			rawESClient.Index.WithDocumentID(id),
+			rawESClient.Index.WithOpType("create"))
+		if err != nil {
+			return fmt.Errorf("terms lookup document save error. %s", err.Error())
+		}
+		if res.StatusCode != http.StatusConflict {
+			err = parseRespDecode(ctx, res, nil)
+		}
+		res.Body.Close()
+		if err != nil {
+			return err
+		}
+		termsLookupSaved.Store(key, true)
+	}
+	return nil
+}
+
+// splitTerms terms values split into should clauses, each clause at most limit values
+func splitTerms(field string, values reflect.Value, limit int) interface{} {
+	b := esQueryBool{MinimumShouldMatch: "1"}
+	for start := 0; start < values.Len(); start += limit {
+		end := start + limit
+		if end > values.Len() {
+			end = values.Len()
+		}
+		b.Should = append(b.Should, terms{name: field, values: values.Slice(start, end).Interface()})
+	}
+	return boolFilter{Bool: b}
+}
@@ -0,0 +1,589 @@
+package ges
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		validate fields of query, sort, aggs and fields against index mapping.
+		eg: unknown field, term on text field, nested field not in nested query, range value not match field type
+
+***************************/
+
+// ValidationKind kind of ValidationFinding
+type ValidationKind string
+
+const (
+	// ValidationUnknownField field not in index mapping
+	ValidationUnknownField ValidationKind = "unknown_field"
+	// ValidationTermOnText term or terms on analyzed text field, exact value seldom match
+	ValidationTermOnText ValidationKind = "term_on_text"
+	// ValidationNestedNotWrapped field in nested object not queried by nested query of its path
+	ValidationNestedNotWrapped ValidationKind = "nested_not_wrapped"
+	// ValidationNotNestedPath path of nested query or nested agg is not nested field
+	ValidationNotNestedPath ValidationKind = "not_nested_path"
+	// ValidationRangeValue range value not match field type
+	ValidationRangeValue ValidationKind = "range_value"
+)
+
+// MappingCacheTTL index mapping cached by Validate expired after MappingCacheTTL
+var MappingCacheTTL = 5 * time.Minute
+
+// ValidationFinding problem of request found by Validate
+type ValidationFinding struct {
+	Kind  ValidationKind
+	Field string
+	// Location part of request. eg: query, sort, aggs.by_day, fields
+	Location string
+	Message  string
+}
+
+func (f ValidationFinding) String() string {
+	return fmt.Sprintf("%s %s: %s", f.Location, f.Kind, f.Message)
+}
+
+type mappingCacheItem struct {
+	schema FieldSchema
+	// formats date format of field with format in mapping
+	formats  map[string]string
+	expireAt time.Time
+}
+
+// mappingCache key is index names joined by comma, share by all clients
+var mappingCache sync.Map
+
+// ResetMappingCache remove cached mapping of indices, no indices remove all
+func ResetMappingCache(indices ...string) {
+	if len(indices) == 0 {
+		mappingCache.Range(func(key, _ interface{}) bool {
+			mappingCache.Delete(key)
+			return true
+		})
+		return
+	}
+	mappingCache.Delete(strings.Join(indices, ","))
+}
+
+// indexSchema field schema and date formats of indices, fetched from elasticsearch when not cached or expired.
+// error if no index resolved, mapping without field not cached
+func indexSchema(ctx context.Context, indices []string) (FieldSchema, map[string]string, error) {
+	key := strings.Join(indices, ",")
+	if item, ok := mappingCache.Load(key); ok && time.Now().Before(item.(mappingCacheItem).expireAt) {
+		return item.(mappingCacheItem).schema, item.(mappingCacheItem).formats, nil
+	}
+	res, err := rawESClient.Indices.GetMapping(
+		rawESClient.Indices.GetMapping.WithContext(ctx),
+		rawESClient.Indices.GetMapping.WithIndex(indices...),
+		rawESClient.Indices.GetMapping.WithIgnoreUnavailable(true),
+		rawESClient.Indices.GetMapping.WithAllowNoIndices(true),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("es client do error. %s", err.Error())
+	}
+	defer res.Body.Close()
+
+	resp := make(map[string]struct {
+		Mappings IndexMapping `json:"mappings"`
+	})
+	if err := parseRespDecode(ctx, res, &resp); err != nil {
+		return nil, nil, err
+	}
+	if len(resp) == 0 {
+		return nil, nil, fmt.Errorf("validate error. index %s not found", key)
+	}
+	schema, formats := make(FieldSchema), make(map[string]string)
+	for _, index := range resp {
+		for field, fieldType := range FieldSchemaFromMapping(index.Mappings.Properties) {
+			schema[field] = fieldType
+		}
+		mappingFormats("", index.Mappings.Properties, formats)
+	}
+	if len(schema) != 0 {
+		mappingCache.Store(key, mappingCacheItem{schema: schema, formats: formats, expireAt: time.Now().Add(MappingCacheTTL)})
+	}
+	return schema, formats, nil
+}
+
+// mappingFormats format of fields, multi-fields included
+func mappingFormats(prefix string, properties map[string]MappingField, formats map[string]string) {
+	for name, field := range properties {
+		fullName := prefix + name
+		if field.Format != "" {
+			formats[fullName] = field.Format
+		}
+		for subName, sub := range field.Fields {
+			if sub.Format != "" {
+				formats[fullName+"."+subName] = sub.Format
+			}
+		}
+		if len(field.Properties) != 0 {
+			mappingFormats(fullName+".", field.Properties, formats)
+		}
+	}
+}
+
+// Validate check fields of Where, Not, Or, sorts, aggs and Fields against index mapping, mapping cached MappingCacheTTL.
+// request is not sent, findings empty not mean request is valid
+func (e es) Validate(ctx context.Context) ([]ValidationFinding, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	indices := e.searchIndices()
+	if len(indices) == 0 || indices[0] == "" {
+		return nil, fmt.Errorf("validate error. index name is empty")
+	}
+	schema, formats, err := indexSchema(ctx, indices)
+	if err != nil {
+		return nil, err
+	}
+	v := &validator{schema: schema, formats: formats}
+	if len(e.runtimeMappings) != 0 {
+		v.schema = make(FieldSchema, len(schema)+len(e.runtimeMappings))
+		for field, fieldType := range schema {
+			v.schema[field] = fieldType
+		}
+		for field, runtime := range e.runtimeMappings {
+			v.schema[field] = runtime.Type
+		}
+	}
+
+	cond := e.condition()
+	query, err := validateJSONValue(cond.Query)
+	if err != nil {
+		return nil, err
+	}
+	v.query(query, "query", "")
+
+	sorts, err := validateJSONValue(cond.Sort)
+	if err != nil {
+		return nil, err
+	}
+	v.sorts(sorts)
+
+	aggs, err := validateJSONValue(cond.Agg)
+	if err != nil {
+		return nil, err
+	}
+	v.aggs(aggs, "aggs", "")
+
+	for _, field := range e.fields {
+		v.sourceField(field)
+	}
+	return v.findings, nil
+}
+
+// validateJSONValue request part marshal to generic json value, number keep as json.Number
+func validateJSONValue(value interface{}) (interface{}, error) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("validate condition build error. %s", err.Error())
+	}
+	var result interface{}
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&result); err != nil {
+		return nil, fmt.Errorf("validate condition build error. %s", err.Error())
+	}
+	return result, nil
+}
+
+// validateParamKeys keys beside field name in body of query, decay function and geo distance sort,
+// other keys are field name. eg: {"terms": {"status": ["a"], "boost": 2}}
+var validateParamKeys = map[string]map[string]bool{
+	"terms": {"boost": true, "_name": true},
+	"geo_distance": {"distance": true, "distance_type": true, "validation_method": true,
+		"ignore_unmapped": true, "boost": true, "_name": true},
+	"geo_bounding_box":  {"type": true, "validation_method": true, "ignore_unmapped": true, "boost": true, "_name": true},
+	"geo_polygon":       {"validation_method": true, "ignore_unmapped": true, "boost": true, "_name": true},
+	"geo_shape":         {"ignore_unmapped": true, "boost": true, "_name": true},
+	string(DecayGauss):  {"multi_value_mode": true},
+	string(DecayExp):    {"multi_value_mode": true},
+	string(DecayLinear): {"multi_value_mode": true},
+	"_geo_distance": {"order": true, "unit": true, "mode": true, "distance_type": true,
+		"ignore_unmapped": true, "validation_method": true, "nested": true},
+}
+
+// validateTermLevelQuery query by field name key
+var validateTermLevelQuery = map[string]bool{
+	"term": true, "terms": true, "match": true, "match_phrase": true, "match_phrase_prefix": true, "match_bool_prefix": true,
+	"prefix": true, "wildcard": true, "regexp": true, "fuzzy": true, "range": true, "terms_set": true,
+	"geo_distance": true, "geo_bounding_box": true, "geo_polygon": true, "geo_shape": true,
+}
+
+type validator struct {
+	schema FieldSchema
+	// formats date format of field in mapping
+	formats  map[string]string
+	findings []ValidationFinding
+}
+
+func (v *validator) add(kind ValidationKind, field, location, format string, args ...interface{}) {
+	v.findings = append(v.findings, ValidationFinding{
+		Kind:     kind,
+		Field:    field,
+		Location: location,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// field type of field, meta field, wildcard field and unknown field not ok
+func (v *validator) field(field, location string) (MappingType, bool) {
+	if field == "" || strings.HasPrefix(field, "_") || strings.Contains(field, "*") {
+		return "", false
+	}
+	fieldType, ok := v.schema[field]
+	if !ok {
+		v.add(ValidationUnknownField, field, location, "field %s not in mapping", field)
+		return "", false
+	}
+	return fieldType, true
+}
+
+// queryField type of field used in query or agg, field in nested object must be in nested of its path
+func (v *validator) queryField(field, location, nested string) (MappingType, bool) {
+	fieldType, ok := v.field(field, location)
+	if !ok {
+		return "", false
+	}
+	if path := v.schema.nestedPath(field); path != "" && path != nested {
+		v.add(ValidationNestedNotWrapped, field, location, "field %s must be in nested query of path %s", field, path)
+	}
+	return fieldType, true
+}
+
+func (v *validator) nestedPath(path, location string) {
+	if fieldType, ok := v.field(path, location); ok && fieldType != MappingTypeNested {
+		v.add(ValidationNotNestedPath, path, location, "path %s is %s, not nested", path, fieldType)
+	}
+}
+
+func (v *validator) query(node interface{}, location, nested string) {
+	switch val := node.(type) {
+	case []interface{}:
+		for _, item := range val {
+			v.query(item, location, nested)
+		}
+		return
+	case map[string]interface{}:
+		for _, kind := range sortedKeys(val) {
+			v.queryKind(kind, val[kind], location, nested)
+		}
+	}
+}
+
+func (v *validator) queryKind(kind string, node interface{}, location, nested string) {
+	body, ok := node.(map[string]interface{})
+	if !ok {
+		return
+	}
+	switch {
+	case kind == "bool":
+		for _, key := range []string{"must", "filter", "should", "must_not"} {
+			v.query(body[key], location, nested)
+		}
+	case kind == "nested":
+		path, _ := body["path"].(string)
+		v.nestedPath(path, location)
+		v.query(body["query"], location, path)
+	case kind == "has_child" || kind == "has_parent":
+		v.query(body["query"], location, "")
+	case kind == "constant_score":
+		v.query(body["filter"], location, nested)
+	case kind == "boosting":
+		v.query(body["positive"], location, nested)
+		v.query(body["negative"], location, nested)
+	case kind == "dis_max":
+		v.query(body["queries"], location, nested)
+	case kind == "script_score":
+		v.query(body["query"], location, nested)
+	case kind == "function_score":
+		v.query(body["query"], location, nested)
+		functions, _ := body["functions"].([]interface{})
+		for _, item := range functions {
+			function, _ := item.(map[string]interface{})
+			v.query(function["filter"], location, nested)
+			if factor, ok := function["field_value_factor"].(map[string]interface{}); ok {
+				field, _ := factor["field"].(string)
+				v.queryField(field, location, nested)
+			}
+			for _, decay := range []DecayType{DecayGauss, DecayExp, DecayLinear} {
+				if params, ok := function[string(decay)].(map[string]interface{}); ok {
+					v.paramFields(string(decay), params, location, nested)
+				}
+			}
+		}
+	case kind == "exists":
+		field, _ := body["field"].(string)
+		v.queryField(field, location, nested)
+	case kind == "multi_match" || kind == "query_string" || kind == "simple_query_string" || kind == "more_like_this":
+		fields, _ := body["fields"].([]interface{})
+		for _, item := range fields {
+			field, _ := item.(string)
+			if idx := strings.Index(field, "^"); idx >= 0 {
+				field = field[:idx]
+			}
+			v.queryField(field, location, nested)
+		}
+	case validateTermLevelQuery[kind]:
+		for _, field := range v.paramFields(kind, body, location, nested) {
+			v.fieldQuery(kind, field, body[field], location)
+		}
+	}
+}
+
+// paramFields check field name keys of params, param keys of kind skipped, return fields in mapping
+func (v *validator) paramFields(kind string, params map[string]interface{}, location, nested string) []string {
+	fields := make([]string, 0, 1)
+	for _, field := range sortedKeys(params) {
+		if validateParamKeys[kind][field] {
+			continue
+		}
+		if _, ok := v.queryField(field, location, nested); ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// fieldQuery check query kind allowed by field type
+func (v *validator) fieldQuery(kind, field string, params interface{}, location string) {
+	fieldType := v.schema[field]
+	switch kind {
+	case "term", "terms":
+		if fieldType == MappingTypeText {
+			v.add(ValidationTermOnText, field, location, "%s on text field %s, use keyword field or match", kind, field)
+		}
+	case "range":
+		bounds, _ := params.(map[string]interface{})
+		// format of range query override format of mapping
+		format, _ := bounds["format"].(string)
+		if format == "" {
+			format = v.formats[field]
+		}
+		for _, key := range []string{"gt", "gte", "lt", "lte", "from", "to"} {
+			if value, ok := bounds[key]; ok && value != nil && !rangeValueMatch(fieldType, format, value) {
+				v.add(ValidationRangeValue, field, location, "range %s value %v not match %s field %s", key, value, fieldType, field)
+			}
+		}
+	}
+}
+
+// rangeValueMatch range bound value allowed by field type, date value is date math or matched by format of date field
+func rangeValueMatch(fieldType MappingType, format string, value interface{}) bool {
+	switch {
+	case isNumericMappingType(fieldType):
+		switch val := value.(type) {
+		case json.Number:
+			return true
+		case string:
+			_, err := strconv.ParseFloat(val, 64)
+			return err == nil
+		}
+		return false
+	case fieldType == MappingTypeDate:
+		switch val := value.(type) {
+		case json.Number:
+			return dateValueMatch(format, val.String())
+		case string:
+			return dateValueMatch(format, val)
+		}
+		return false
+	case fieldType == MappingTypeText || fieldType == MappingTypeBoolean || fieldType == MappingTypeNested:
+		return false
+	}
+	return true
+}
+
+// defaultDateFormat format of date field without format in mapping
+const defaultDateFormat = "strict_date_optional_time||epoch_millis"
+
+// isoDateLayouts layouts of date_optional_time, date and time parts after year are optional
+var isoDateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02T15:04", "2006-01-02T15",
+	"2006-01-02", "2006-01", "2006"}
+
+// builtinDateLayouts layouts of built-in date formats
+var builtinDateLayouts = map[string][]string{
+	"strict_date_optional_time":       isoDateLayouts,
+	"date_optional_time":              isoDateLayouts,
+	"strict_date_optional_time_nanos": isoDateLayouts,
+	"date_time":                       {time.RFC3339Nano},
+	"strict_date_time":                {time.RFC3339Nano},
+	"date_time_no_millis":             {time.RFC3339},
+	"strict_date_time_no_millis":      {time.RFC3339},
+	"date":                            {"2006-01-02"},
+	"strict_date":                     {"2006-01-02"},
+	"basic_date":                      {"20060102"},
+	"year_month_day":                  {"2006-01-02"},
+	"strict_year_month_day":           {"2006-01-02"},
+	"date_hour_minute_second":         {"2006-01-02T15:04:05"},
+	"strict_date_hour_minute_second":  {"2006-01-02T15:04:05"},
+}
+
+// dateValueMatch value is date math starting with now, date math with anchor date||, or matched by one of format.
+// epoch_millis and epoch_second accept number, format can not be checked is treated as matched
+func dateValueMatch(format, value string) bool {
+	if strings.HasPrefix(value, "now") {
+		return true
+	}
+	if idx := strings.Index(value, "||"); idx >= 0 {
+		value = value[:idx]
+	}
+	if format == "" {
+		format = defaultDateFormat
+	}
+	for _, item := range strings.Split(format, "||") {
+		item = strings.TrimSpace(item)
+		switch item {
+		case "epoch_millis", "epoch_second":
+			if _, err := strconv.ParseFloat(value, 64); err == nil {
+				return true
+			}
+			continue
+		}
+		layouts, ok := builtinDateLayouts[item]
+		if !ok {
+			layout, ok := javaDateLayout(item)
+			if !ok {
+				return true
+			}
+			layouts = []string{layout}
+		}
+		for _, layout := range layouts {
+			if _, err := time.Parse(layout, value); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// javaDateLayouts layout of java date pattern letters, longest first
+var javaDateLayouts = []struct{ pattern, layout string }{
+	{"yyyy", "2006"}, {"uuuu", "2006"}, {"yy", "06"}, {"MMMM", "January"}, {"MMM", "Jan"}, {"MM", "01"}, {"M", "1"},
+	{"dd", "02"}, {"d", "2"}, {"HH", "15"}, {"hh", "03"}, {"h", "3"}, {"mm", "04"}, {"m", "4"},
+	{"ss", "05"}, {"s", "5"}, {"SSSSSSSSS", "000000000"}, {"SSSSSS", "000000"}, {"SSS", "000"}, {"a", "PM"},
+	{"XXX", "Z07:00"}, {"XX", "Z0700"}, {"ZZZ", "-07:00"}, {"Z", "-0700"},
+}
+
+// javaDateLayout go time layout of java date pattern. eg: yyyy-MM-dd HH:mm:ss, false if pattern has unknown letter
+func javaDateLayout(pattern string) (string, bool) {
+	var sb strings.Builder
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c == '\'' {
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end < 0 {
+				return "", false
+			}
+			sb.WriteString(pattern[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+		matched := false
+		for _, item := range javaDateLayouts {
+			if strings.HasPrefix(pattern[i:], item.pattern) {
+				sb.WriteString(item.layout)
+				i += len(item.pattern)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return "", false
+		}
+	}
+	return sb.String(), true
+}
+
+func (v *validator) sorts(node interface{}) {
+	sorts, _ := node.([]interface{})
+	for _, item := range sorts {
+		sortItem, _ := item.(map[string]interface{})
+		for _, field := range sortedKeys(sortItem) {
+			if field != "_geo_distance" {
+				v.field(field, "sort")
+				continue
+			}
+			params, _ := sortItem[field].(map[string]interface{})
+			for _, geoField := range sortedKeys(params) {
+				if !validateParamKeys[field][geoField] {
+					v.field(geoField, "sort")
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) aggs(node interface{}, location, nested string) {
+	aggs, _ := node.(map[string]interface{})
+	for _, name := range sortedKeys(aggs) {
+		body, _ := aggs[name].(map[string]interface{})
+		aggLocation := location + "." + name
+		aggNested := nested
+		if params, ok := body["nested"].(map[string]interface{}); ok {
+			aggNested, _ = params["path"].(string)
+			v.nestedPath(aggNested, aggLocation)
+		}
+		if _, ok := body["reverse_nested"]; ok {
+			aggNested = ""
+		}
+		for _, kind := range sortedKeys(body) {
+			switch kind {
+			case "nested", "reverse_nested":
+			case "aggs", "aggregations":
+				v.aggs(body[kind], aggLocation, aggNested)
+			case "filter":
+				v.query(body[kind], aggLocation, aggNested)
+			case "filters":
+				params, _ := body[kind].(map[string]interface{})
+				filters, _ := params["filters"].(map[string]interface{})
+				for _, key := range sortedKeys(filters) {
+					v.query(filters[key], aggLocation, aggNested)
+				}
+			default:
+				params, _ := body[kind].(map[string]interface{})
+				if field, ok := params["field"].(string); ok {
+					v.queryField(field, aggLocation, aggNested)
+				}
+			}
+		}
+	}
+}
+
+// sourceField field of _source includes, object field and wildcard allowed
+func (v *validator) sourceField(field string) {
+	if _, ok := v.schema[field]; ok || strings.Contains(field, "*") || strings.HasPrefix(field, "_") {
+		return
+	}
+	for name := range v.schema {
+		if strings.HasPrefix(name, field+".") {
+			return
+		}
+	}
+	v.add(ValidationUnknownField, field, "fields", "field %s not in mapping", field)
+}
@@ -0,0 +1,136 @@
+package ges
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+
+***************************/
+
+func TestValidate(t *testing.T) {
+	schema := FieldSchemaFromMapping(map[string]MappingField{
+		"status":     {Type: MappingTypeKeyword},
+		"title":      {Type: MappingTypeText, Fields: map[string]MappingField{"keyword": {Type: MappingTypeKeyword}}},
+		"priority":   {Type: MappingTypeInteger},
+		"created_at": {Type: MappingTypeDate},
+		"unit":       {Type: MappingTypeText},
+		"user":       {Properties: map[string]MappingField{"name": {Type: MappingTypeKeyword}}},
+		"items": {Type: MappingTypeNested, Properties: map[string]MappingField{
+			"sku": {Type: MappingTypeKeyword},
+		}},
+	})
+	mappingCache.Store("ticket", mappingCacheItem{schema: schema, expireAt: time.Now().Add(time.Hour)})
+	defer ResetMappingCache("ticket")
+
+	client := ES().IndexName("ticket").
+		Where(Term("status", "open"), Term("title", "quick fox"), Gte("priority", "high"), Term("items.sku", "a"),
+			Term("unit", "kg"), Term("format", "pdf")).
+		Not(filter{}.Nested(NestedQuery("items", Term("items.sku", "b"), nil, nil, nil))).
+		Or(Terms("stauts", []string{"a"}), filter{}.Nested(NestedQuery("status", Exists("status"), nil, nil, nil))).
+		OrderBy("created_at", true).OrderBy("title.keyword", false).OrderBy("_score", true).OrderBy("createdAt", false).
+		Agg(AggDistinct("user.name", 10), AggSum("total", "amount"), AggNested("items", "items").Aggs(AggDistinct("items.sku", 10))).
+		Fields("user", "title", "tittle")
+	findings, err := client.Validate(context.Background())
+	require.NoError(t, err, "TestValidate Validate")
+	actual := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		actual = append(actual, finding.String())
+	}
+	expected := []string{
+		"query term_on_text: term on text field title, use keyword field or match",
+		"query range_value: range gte value high not match integer field priority",
+		"query nested_not_wrapped: field items.sku must be in nested query of path items",
+		"query term_on_text: term on text field unit, use keyword field or match",
+		"query unknown_field: field format not in mapping",
+		"query unknown_field: field stauts not in mapping",
+		"query not_nested_path: path status is keyword, not nested",
+		"sort unknown_field: field createdAt not in mapping",
+		"aggs.total unknown_field: field amount not in mapping",
+		"fields unknown_field: field tittle not in mapping",
+	}
+	require.Equal(t, expected, actual, "TestValidate")
+}
+
+func TestValidateDateRange(t *testing.T) {
+	properties := map[string]MappingField{
+		"created_at": {Type: MappingTypeDate},
+		"closed_at":  {Type: MappingTypeDate, Format: "yyyy-MM-dd HH:mm:ss||epoch_millis"},
+	}
+	formats := make(map[string]string)
+	mappingFormats("", properties, formats)
+	mappingCache.Store("ticket", mappingCacheItem{schema: FieldSchemaFromMapping(properties), formats: formats,
+		expireAt: time.Now().Add(time.Hour)})
+	defer ResetMappingCache("ticket")
+
+	client := ES().IndexName("ticket").Where(
+		Gte("created_at", "now-1d/d"), Lt("created_at", "2024-01-02T10:00:00Z"), Gte("created_at", "2024-01-01||+1M"),
+		Gt("created_at", 1700000000000), Gt("created_at", "yesterday"),
+		Lte("closed_at", "2024-01-02 10:00:00"), Gte("closed_at", 1700000000000), Lt("closed_at", "2024-01-02T10:00:00Z"))
+	findings, err := client.Validate(context.Background())
+	require.NoError(t, err, "TestValidateDateRange Validate")
+	actual := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		actual = append(actual, finding.String())
+	}
+	expected := []string{
+		"query range_value: range gt value yesterday not match date field created_at",
+		"query range_value: range lt value 2024-01-02T10:00:00Z not match date field closed_at",
+	}
+	require.Equal(t, expected, actual, "TestValidateDateRange")
+
+	for value, expected := range map[string]bool{
+		"01/02/2024":    true,
+		"2024-01-02":    false,
+		"01/02/2024 12": false,
+	} {
+		require.Equal(t, expected, dateValueMatch("dd/MM/yyyy", value), "TestValidateDateRange format "+value)
+	}
+	require.True(t, dateValueMatch("custom_format", "anything"), "TestValidateDateRange unknown format")
+}
+
+func TestValidateIndexSchema(t *testing.T) {
+	var requests int32
+	restore := mockESServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/ticket/_mapping":
+			_, _ = w.Write([]byte(`{"ticket":{"mappings":{"properties":{"status":{"type":"keyword"},` +
+				`"created_at":{"type":"date","format":"yyyy-MM-dd"}}}}}`))
+		case "/empty/_mapping":
+			_, _ = w.Write([]byte(`{"empty":{"mappings":{}}}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	})
+	defer restore()
+	defer ResetMappingCache()
+
+	_, err := ES().IndexName("missing").Where(Term("status", "open")).Validate(context.Background())
+	require.Error(t, err, "TestValidateIndexSchema missing index")
+	_, cached := mappingCache.Load("missing")
+	require.False(t, cached, "TestValidateIndexSchema missing index not cached")
+
+	findings, err := ES().IndexName("empty").Where(Term("status", "open")).Validate(context.Background())
+	require.NoError(t, err, "TestValidateIndexSchema empty mapping")
+	require.Len(t, findings, 1, "TestValidateIndexSchema empty mapping")
+	_, cached = mappingCache.Load("empty")
+	require.False(t, cached, "TestValidateIndexSchema empty mapping not cached")
+
+	atomic.StoreInt32(&requests, 0)
+	for i := 0; i < 2; i++ {
+		findings, err = ES().IndexName("ticket").Where(Term("status", "open"), Gte("created_at", "2024-01-01")).
+			Validate(context.Background())
+		require.NoError(t, err, "TestValidateIndexSchema ticket")
+		require.Empty(t, findings, "TestValidateIndexSchema ticket")
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests), "TestValidateIndexSchema mapping cached")
+}
@@ -50,14 +50,28 @@ func (t terms) MarshalJSON() ([]byte, error) {
 	})
 }
 
+type exists struct {
+	name string
+}
+
+func (e exists) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]map[string]interface{}{
+		"exists": {
+			"field": e.name,
+		},
+	})
+}
+
 type wildCard struct {
 	name string
 	wildCardValue
 }
 
 type wildCardValue struct {
-	Wildcard string  `json:"wildcard"`
-	Boost    float64 `json:"boost"`
+	Wildcard        string  `json:"wildcard"`
+	Boost           float64 `json:"boost,omitempty"`
+	CaseInsensitive bool    `json:"case_insensitive,omitempty"`
+	Rewrite         string  `json:"rewrite,omitempty"`
 }
 
 func (wcv wildCard) MarshalJSON() ([]byte, error) {
@@ -77,6 +91,11 @@ type boolFilter struct {
 type esCondition struct {
 	Query esConditionQuery       `json:"query"`
 	Agg   map[string]interface{} `json:"aggs,omitempty"`
+	Sort  []interface{}          `json:"sort,omitempty"`
+	// RuntimeMappings search time runtime fields
+	RuntimeMappings map[string]RuntimeField `json:"runtime_mappings,omitempty"`
+	// Fields runtime fields returned by fields of hit
+	Fields []string `json:"fields,omitempty"`
 }
 
 type esConditionSortOrder struct {
@@ -101,9 +120,11 @@ func (b esConditionQuery) MarshalJSON() ([]byte, error) {
 
 type esQueryBool struct {
 	Must               []interface{} `json:"must,omitempty"`
+	Filter             []interface{} `json:"filter,omitempty"`
 	Not                []interface{} `json:"must_not,omitempty"`
 	Should             []interface{} `json:"should,omitempty"`
 	Match              []interface{} `json:"match,omitempty"`
+	MinimumShouldMatch string        `json:"minimum_should_match,omitempty"`
 	AdjustPureNegative bool          `json:"adjust_pure_negative,omitempty"`
 }
 
@@ -139,7 +160,7 @@ func (f filter) Terms(field string, values interface{}) Filter {
 func (f filter) FromTo(field string, from, to interface{}) Filter {
 	b := between{name: field}
 
-	b.GtePtr, b.LtPtr = from, to
+	b.GtePtr, b.LtPtr = rangeValue(from), rangeValue(to)
 	f.condition = append(f.condition, b)
 	return f
 }
@@ -147,71 +168,111 @@ func (f filter) FromTo(field string, from, to interface{}) Filter {
 // Range  range query. [start, end], start <= field <= end
 func (f filter) Range(field string, start, end interface{}) Filter {
 	b := between{name: field}
-	b.GtePtr, b.LtePtr = start, end
+	b.GtePtr, b.LtePtr = rangeValue(start), rangeValue(end)
+	f.condition = append(f.condition, b)
+	return f
+}
+
+// RangeWith range query with bound and options of RangeFilter. eg: format, time_zone, relation
+func (f filter) RangeWith(field string, r RangeFilter) Filter {
+	b := between{name: field}
+	switch val := r.(type) {
+	case betweenValue:
+		b.betweenValue = val
+	case *betweenValue:
+		b.betweenValue = *val
+	default:
+		f.condition = append(f.condition, map[string]map[string]interface{}{"range": {field: r}})
+		return f
+	}
 	f.condition = append(f.condition, b)
 	return f
 }
 
 // Between  range query. [start, end], start <= field <= end
-func (f filter) Between(field string, start, end int64) Filter {
+func (f filter) Between(field string, start, end interface{}) Filter {
 	b := between{name: field}
-	b.GtePtr, b.LtePtr = start, end
+	b.GtePtr, b.LtePtr = rangeValue(start), rangeValue(end)
 	f.condition = append(f.condition, b)
 	return f
 }
 
-func (f filter) Gt(field string, value int64) Filter {
+func (f filter) Gt(field string, value interface{}) Filter {
 	b := between{name: field}
-	b.GtPtr = value
+	b.GtPtr = rangeValue(value)
 	f.condition = append(f.condition, b)
 	return f
 }
 
-func (f filter) Gte(field string, value int64) Filter {
+func (f filter) Gte(field string, value interface{}) Filter {
 	b := between{name: field}
-	b.GtePtr = value
+	b.GtePtr = rangeValue(value)
 	f.condition = append(f.condition, b)
 	return f
 }
 
-func (f filter) Lt(field string, value int64) Filter {
+func (f filter) Lt(field string, value interface{}) Filter {
 	b := between{name: field}
-	b.LtPtr = value
+	b.LtPtr = rangeValue(value)
 	f.condition = append(f.condition, b)
 	return f
 }
 
-func (f filter) Lte(field string, value int64) Filter {
+func (f filter) Lte(field string, value interface{}) Filter {
 	b := between{name: field}
 
-	b.LtePtr = value
+	b.LtePtr = rangeValue(value)
 	f.condition = append(f.condition, b)
 	return f
 }
 
-func (f filter) Wildcard(field string, value string) Filter {
+func (f filter) Wildcard(field string, value string, opts ...TermOptions) Filter {
+	opt := firstTermOptions(opts)
 	b := wildCard{name: field}
 	b.Wildcard = value
+	b.Boost, b.CaseInsensitive, b.Rewrite = opt.Boost, opt.CaseInsensitive, opt.Rewrite
 	f.condition = append(f.condition, b)
 	return f
 }
 
-func (f filter) WildcardSuffix(field string, value string) Filter {
-	b := wildCard{name: field}
-	b.Wildcard = value + "*"
-	f.condition = append(f.condition, b)
+func (f filter) WildcardSuffix(field string, value string, opts ...TermOptions) Filter {
+	return f.Wildcard(field, value+"*", opts...)
+}
+
+// Exists field has an indexed value
+func (f filter) Exists(field string) Filter {
+	f.condition = append(f.condition, exists{name: field})
+	return f
+}
+
+// Missing field has no indexed value, bool must_not exists
+func (f filter) Missing(field string) Filter {
+	b := esQueryBool{Not: []interface{}{exists{name: field}}}
+	f.condition = append(f.condition, boolFilter{Bool: b})
 	return f
 }
 
+// BoolOptions options of bool query
+type BoolOptions struct {
+	// MinimumShouldMatch number or percentage of should conditions must match. eg: 2, 75%
+	MinimumShouldMatch string
+	AdjustPureNegative bool
+}
+
 // BoolItem 用于构建bool查询, 后期需要优化，暴露出来 bool query 对象，用来管理条件，
 func (f filter) BoolItem(must, not, should, match Filter, adjustPureNegative bool) Filter {
+	return f.BoolWith(must, not, should, match, BoolOptions{AdjustPureNegative: adjustPureNegative})
+}
+
+// BoolWith bool query with options. eg: at least 2 of should conditions match
+func (f filter) BoolWith(must, not, should, match Filter, opts BoolOptions) Filter {
 	if must == nil && not == nil && should == nil && match == nil {
 		return f
 	}
 
 	b := esQueryBool{
-
-		AdjustPureNegative: adjustPureNegative,
+		MinimumShouldMatch: opts.MinimumShouldMatch,
+		AdjustPureNegative: opts.AdjustPureNegative,
 	}
 	if must != nil {
 		b.Must = must.Result()
@@ -230,6 +291,38 @@ func (f filter) BoolItem(must, not, should, match Filter, adjustPureNegative boo
 	return f
 }
 
+// BoolFilter bool query with filter context, conditions not affect score and can be cached
+func (f filter) BoolFilter(filters ...Filter) Filter {
+	b := esQueryBool{}
+	for _, item := range filters {
+		if item == nil {
+			continue
+		}
+		b.Filter = append(b.Filter, item.Result()...)
+	}
+	if len(b.Filter) == 0 {
+		return f
+	}
+	f.condition = append(f.condition, boolFilter{Bool: b})
+	return f
+}
+
+// MinimumShouldMatch bool query, at least minimumShouldMatch of should conditions must match. eg: 2, 75%
+func (f filter) MinimumShouldMatch(minimumShouldMatch string, should ...Filter) Filter {
+	b := esQueryBool{MinimumShouldMatch: minimumShouldMatch}
+	for _, item := range should {
+		if item == nil {
+			continue
+		}
+		b.Should = append(b.Should, item.Result()...)
+	}
+	if len(b.Should) == 0 {
+		return f
+	}
+	f.condition = append(f.condition, boolFilter{Bool: b})
+	return f
+}
+
 func (f filter) Nested(nestedFilter NestedFilter) Filter {
 	f.condition = append(f.condition, nestedFilter.Result())
 	return f
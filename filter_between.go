@@ -2,6 +2,7 @@ package ges
 
 import (
 	"encoding/json"
+	"time"
 )
 
 /***************************
@@ -16,47 +17,99 @@ type between struct {
 	betweenValue
 }
 
+// RangeRelation relation between query range and range field value
+type RangeRelation string
+
+const (
+	RangeIntersects RangeRelation = "INTERSECTS"
+	RangeContains   RangeRelation = "CONTAINS"
+	RangeWithin     RangeRelation = "WITHIN"
+)
+
+// betweenValue range bound value support number, string, date math(eg: now-7d/d) and time.Time
 type betweenValue struct {
 	LtePtr interface{} `json:"lte,omitempty"`
 	LtPtr  interface{} `json:"lt,omitempty"`
 	GtePtr interface{} `json:"gte,omitempty"`
 	GtPtr  interface{} `json:"gt,omitempty"`
+	// FormatStr date format of bound value. eg: yyyy-MM-dd, epoch_second
+	FormatStr   string        `json:"format,omitempty"`
+	TimeZoneStr string        `json:"time_zone,omitempty"`
+	RelationStr RangeRelation `json:"relation,omitempty"`
+	BoostVal    float64       `json:"boost,omitempty"`
+}
+
+// rangeValue time.Time convert to RFC3339 string, other value keep raw
+func rangeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case *time.Time:
+		if val == nil {
+			return nil
+		}
+		return val.Format(time.RFC3339Nano)
+	}
+	return v
 }
 
 // FromTo  range query. [from, to), from <= field < to
 func (f betweenValue) FromTo(from, to interface{}) RangeFilter {
-	f.GtePtr, f.LtPtr = from, to
+	f.GtePtr, f.LtPtr = rangeValue(from), rangeValue(to)
 	return f
 }
 
 func (f betweenValue) Range(start, end interface{}) RangeFilter {
-	f.GtePtr, f.LtePtr = start, end
+	f.GtePtr, f.LtePtr = rangeValue(start), rangeValue(end)
 	return f
 }
 
 func (f betweenValue) Between(start, end interface{}) RangeFilter {
-	f.GtePtr, f.LtePtr = start, end
+	f.GtePtr, f.LtePtr = rangeValue(start), rangeValue(end)
 	return f
 }
 
 func (f betweenValue) Gt(value interface{}) RangeFilter {
-	f.GtPtr = value
+	f.GtPtr = rangeValue(value)
 	return f
 }
 
 func (f betweenValue) Gte(value interface{}) RangeFilter {
-	f.GtePtr = value
+	f.GtePtr = rangeValue(value)
 	return f
 }
 
 func (f betweenValue) Lt(value interface{}) RangeFilter {
-	f.LtPtr = value
+	f.LtPtr = rangeValue(value)
 	return f
 }
 
 func (f betweenValue) Lte(value interface{}) RangeFilter {
 
-	f.LtePtr = value
+	f.LtePtr = rangeValue(value)
+	return f
+}
+
+// Format date format of bound value, override format of field mapping
+func (f betweenValue) Format(format string) RangeFilter {
+	f.FormatStr = format
+	return f
+}
+
+// TimeZone convert date bound value to UTC. eg: +08:00, Asia/Shanghai
+func (f betweenValue) TimeZone(timeZone string) RangeFilter {
+	f.TimeZoneStr = timeZone
+	return f
+}
+
+// Relation only for range field
+func (f betweenValue) Relation(relation RangeRelation) RangeFilter {
+	f.RelationStr = relation
+	return f
+}
+
+func (f betweenValue) Boost(boost float64) RangeFilter {
+	f.BoostVal = boost
 	return f
 }
 
@@ -0,0 +1,55 @@
+package ges
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		compound query. constant_score, boosting, dis_max
+
+***************************/
+
+// ConstantScore documents match filter get same score boost, filter run in filter context. boost 0 use elasticsearch default 1
+func (f filter) ConstantScore(filter Filter, boost float64) Filter {
+	value := map[string]interface{}{"filter": singleQuery(filter)}
+	if boost != 0 {
+		value["boost"] = boost
+	}
+	f.condition = append(f.condition, map[string]interface{}{"constant_score": value})
+	return f
+}
+
+// Boosting documents match positive, score of documents also match negative multiply negativeBoost(0~1).
+// nil or empty negative demote nothing, only positive query added
+func (f filter) Boosting(positive, negative Filter, negativeBoost float64) Filter {
+	if negative == nil || len(negative.Result()) == 0 {
+		f.condition = append(f.condition, singleQuery(positive))
+		return f
+	}
+	value := map[string]interface{}{
+		"positive":       singleQuery(positive),
+		"negative":       singleQuery(negative),
+		"negative_boost": negativeBoost,
+	}
+	f.condition = append(f.condition, map[string]interface{}{"boosting": value})
+	return f
+}
+
+// DisMax documents match any of queries, score is max score of matched queries plus tieBreaker * other scores
+func (f filter) DisMax(tieBreaker float64, queries ...Filter) Filter {
+	items := make([]interface{}, 0, len(queries))
+	for _, query := range queries {
+		if query == nil || len(query.Result()) == 0 {
+			continue
+		}
+		items = append(items, singleQuery(query))
+	}
+	if len(items) == 0 {
+		return f
+	}
+	value := map[string]interface{}{"queries": items}
+	if tieBreaker != 0 {
+		value["tie_breaker"] = tieBreaker
+	}
+	f.condition = append(f.condition, map[string]interface{}{"dis_max": value})
+	return f
+}
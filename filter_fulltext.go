@@ -0,0 +1,171 @@
+package ges
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		full text query. match, match_phrase, match_phrase_prefix, multi_match, query_string, simple_query_string
+
+***************************/
+
+type MultiMatchType string
+
+const (
+	MultiMatchBestFields   MultiMatchType = "best_fields"
+	MultiMatchMostFields   MultiMatchType = "most_fields"
+	MultiMatchCrossFields  MultiMatchType = "cross_fields"
+	MultiMatchPhrase       MultiMatchType = "phrase"
+	MultiMatchPhrasePrefix MultiMatchType = "phrase_prefix"
+	MultiMatchBoolPrefix   MultiMatchType = "bool_prefix"
+)
+
+// MatchOptions options of full text query, zero value not send.
+// option not supported by the query is ignored. eg: match_phrase ignore Operator
+type MatchOptions struct {
+	// Operator and, or. default_operator of query_string and simple_query_string
+	Operator string
+	// Fuzziness AUTO, 0, 1, 2
+	Fuzziness          string
+	Analyzer           string
+	MinimumShouldMatch string
+	Boost              float64
+	// Slop max positions allowed between matching phrase tokens. phrase_slop of query_string
+	Slop          int
+	PrefixLength  int
+	MaxExpansions int
+	// ZeroTermsQuery none, all. result when analyzer removes all tokens
+	ZeroTermsQuery string
+	Lenient        bool
+	// Type multi_match, query_string type
+	Type       MultiMatchType
+	TieBreaker float64
+	// Fields query_string, simple_query_string fields. eg: FieldBoost("title", 3)
+	Fields []string
+	// DefaultField query_string default field
+	DefaultField string
+	// Flags simple_query_string enabled operators. eg: AND|OR|PREFIX
+	Flags string
+}
+
+func (o MatchOptions) params(keys ...string) map[string]interface{} {
+	all := map[string]interface{}{
+		"operator":             o.Operator,
+		"default_operator":     o.Operator,
+		"fuzziness":            o.Fuzziness,
+		"analyzer":             o.Analyzer,
+		"minimum_should_match": o.MinimumShouldMatch,
+		"boost":                o.Boost,
+		"slop":                 o.Slop,
+		"phrase_slop":          o.Slop,
+		"prefix_length":        o.PrefixLength,
+		"max_expansions":       o.MaxExpansions,
+		"zero_terms_query":     o.ZeroTermsQuery,
+		"lenient":              o.Lenient,
+		"type":                 o.Type,
+		"tie_breaker":          o.TieBreaker,
+		"fields":               o.Fields,
+		"default_field":        o.DefaultField,
+		"flags":                o.Flags,
+	}
+	return pickParams(all, keys...)
+}
+
+// pickParams params of keys, zero value is omitted
+func pickParams(all map[string]interface{}, keys ...string) map[string]interface{} {
+	result := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		if val, ok := all[key]; ok && val != nil && !reflect.ValueOf(val).IsZero() {
+			result[key] = val
+		}
+	}
+	return result
+}
+
+func firstMatchOptions(opts []MatchOptions) MatchOptions {
+	if len(opts) == 0 {
+		return MatchOptions{}
+	}
+	return opts[0]
+}
+
+// FieldBoost field with boost of multi field query. eg: title^3
+func FieldBoost(field string, boost float64) string {
+	return field + "^" + strconv.FormatFloat(boost, 'f', -1, 64)
+}
+
+// paramQuery query with params, field empty for multi field query. eg: multi_match
+type paramQuery struct {
+	kind   string
+	field  string
+	params map[string]interface{}
+}
+
+func (t paramQuery) MarshalJSON() ([]byte, error) {
+	if t.field == "" {
+		return json.Marshal(map[string]map[string]interface{}{
+			t.kind: t.params,
+		})
+	}
+	return json.Marshal(map[string]map[string]map[string]interface{}{
+		t.kind: {
+			t.field: t.params,
+		},
+	})
+}
+
+// MatchWith match query with options
+func (f filter) MatchWith(field string, value interface{}, opts ...MatchOptions) Filter {
+	params := firstMatchOptions(opts).params("operator", "fuzziness", "analyzer", "minimum_should_match", "boost",
+		"prefix_length", "max_expansions", "zero_terms_query", "lenient")
+	params["query"] = value
+	f.condition = append(f.condition, paramQuery{kind: "match", field: field, params: params})
+	return f
+}
+
+func (f filter) MatchPhrase(field string, text string, opts ...MatchOptions) Filter {
+	params := firstMatchOptions(opts).params("analyzer", "slop", "boost", "zero_terms_query")
+	params["query"] = text
+	f.condition = append(f.condition, paramQuery{kind: "match_phrase", field: field, params: params})
+	return f
+}
+
+func (f filter) MatchPhrasePrefix(field string, text string, opts ...MatchOptions) Filter {
+	params := firstMatchOptions(opts).params("analyzer", "slop", "max_expansions", "boost", "zero_terms_query")
+	params["query"] = text
+	f.condition = append(f.condition, paramQuery{kind: "match_phrase_prefix", field: field, params: params})
+	return f
+}
+
+// MultiMatch match text on multi fields, field support boost. eg: FieldBoost("title", 3)
+func (f filter) MultiMatch(text string, fields []string, opts ...MatchOptions) Filter {
+	params := firstMatchOptions(opts).params("type", "operator", "fuzziness", "analyzer", "minimum_should_match",
+		"boost", "tie_breaker", "slop", "prefix_length", "max_expansions", "zero_terms_query", "lenient")
+	params["query"] = text
+	params["fields"] = fields
+	f.condition = append(f.condition, paramQuery{kind: "multi_match", params: params})
+	return f
+}
+
+// QueryString lucene query syntax, invalid syntax return error by elasticsearch
+func (f filter) QueryString(query string, opts ...MatchOptions) Filter {
+	params := firstMatchOptions(opts).params("default_field", "fields", "default_operator", "fuzziness", "analyzer",
+		"minimum_should_match", "boost", "type", "tie_breaker", "phrase_slop", "lenient")
+	params["query"] = query
+	f.condition = append(f.condition, paramQuery{kind: "query_string", params: params})
+	return f
+}
+
+// SimpleQueryString simple query syntax, invalid syntax is ignored
+func (f filter) SimpleQueryString(query string, opts ...MatchOptions) Filter {
+	params := firstMatchOptions(opts).params("fields", "default_operator", "analyzer", "flags",
+		"minimum_should_match", "boost", "lenient")
+	params["query"] = query
+	f.condition = append(f.condition, paramQuery{kind: "simple_query_string", params: params})
+	return f
+}
@@ -0,0 +1,230 @@
+package ges
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		geo query. geo_distance, geo_bounding_box, geo_polygon, geo_shape
+
+***************************/
+
+// GeoPoint encode as {"lat": 1, "lon": 2}, decode all elasticsearch geo_point formats:
+// object, [lon, lat] array, "lat,lon" string, geohash, WKT POINT(lon lat), GeoJSON point
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+func NewGeoPoint(lat, lon float64) GeoPoint {
+	return GeoPoint{Lat: lat, Lon: lon}
+}
+
+func (p GeoPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]float64{"lat": p.Lat, "lon": p.Lon})
+}
+
+func (p *GeoPoint) UnmarshalJSON(data []byte) error {
+	raw := strings.TrimSpace(string(data))
+	if raw == "null" {
+		return nil
+	}
+	switch raw[0] {
+	case '[':
+		var lonLat []float64
+		if err := json.Unmarshal(data, &lonLat); err != nil {
+			return fmt.Errorf("geo point array decode error. %s", err.Error())
+		}
+		if len(lonLat) < 2 {
+			return fmt.Errorf("geo point array need [lon, lat], got: %s", raw)
+		}
+		p.Lon, p.Lat = lonLat[0], lonLat[1]
+		return nil
+	case '"':
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		return p.parseString(str)
+	case '{':
+		obj := struct {
+			Lat         *float64  `json:"lat"`
+			Lon         *float64  `json:"lon"`
+			Type        string    `json:"type"`
+			Coordinates []float64 `json:"coordinates"`
+		}{}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return fmt.Errorf("geo point object decode error. %s", err.Error())
+		}
+		if obj.Lat != nil && obj.Lon != nil {
+			p.Lat, p.Lon = *obj.Lat, *obj.Lon
+			return nil
+		}
+		if strings.EqualFold(obj.Type, "point") && len(obj.Coordinates) >= 2 {
+			p.Lon, p.Lat = obj.Coordinates[0], obj.Coordinates[1]
+			return nil
+		}
+	}
+	return fmt.Errorf("geo point format not support: %s", raw)
+}
+
+func (p *GeoPoint) parseString(str string) error {
+	str = strings.TrimSpace(str)
+	upper := strings.ToUpper(str)
+	switch {
+	case strings.HasPrefix(upper, "POINT"):
+		// WKT POINT (lon lat)
+		body := strings.Trim(strings.TrimSpace(str[len("POINT"):]), "()")
+		items := strings.Fields(body)
+		if len(items) < 2 {
+			return fmt.Errorf("geo point wkt format error: %s", str)
+		}
+		return p.parseLatLon(items[1], items[0], str)
+	case strings.Contains(str, ","):
+		items := strings.Split(str, ",")
+		return p.parseLatLon(items[0], items[1], str)
+	default:
+		lat, lon, err := decodeGeohash(str)
+		if err != nil {
+			return err
+		}
+		p.Lat, p.Lon = lat, lon
+		return nil
+	}
+}
+
+func (p *GeoPoint) parseLatLon(latStr, lonStr, raw string) error {
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil {
+		return fmt.Errorf("geo point lat error: %s", raw)
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+	if err != nil {
+		return fmt.Errorf("geo point lon error: %s", raw)
+	}
+	p.Lat, p.Lon = lat, lon
+	return nil
+}
+
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// decodeGeohash center of geohash cell
+func decodeGeohash(hash string) (float64, float64, error) {
+	if hash == "" {
+		return 0, 0, fmt.Errorf("geo point geohash empty")
+	}
+	latRange, lonRange := [2]float64{-90, 90}, [2]float64{-180, 180}
+	isLon := true
+	for _, c := range strings.ToLower(hash) {
+		idx := strings.IndexRune(geohashBase32, c)
+		if idx < 0 {
+			return 0, 0, fmt.Errorf("geo point geohash invalid character %q: %s", c, hash)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			r := &latRange
+			if isLon {
+				r = &lonRange
+			}
+			mid := (r[0] + r[1]) / 2
+			if idx&(1<<uint(bit)) != 0 {
+				r[0] = mid
+			} else {
+				r[1] = mid
+			}
+			isLon = !isLon
+		}
+	}
+	return (latRange[0] + latRange[1]) / 2, (lonRange[0] + lonRange[1]) / 2, nil
+}
+
+// GeoShapeRelation spatial relation of geo_shape query
+type GeoShapeRelation string
+
+const (
+	GeoShapeIntersects GeoShapeRelation = "INTERSECTS"
+	GeoShapeDisjoint   GeoShapeRelation = "DISJOINT"
+	GeoShapeWithin     GeoShapeRelation = "WITHIN"
+	GeoShapeContains   GeoShapeRelation = "CONTAINS"
+)
+
+// GeoGeometry GeoJSON geometry of geo_shape query, coordinates order is [lon, lat]
+type GeoGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// GeoEnvelope bounding rectangle shape
+func GeoEnvelope(topLeft, bottomRight GeoPoint) GeoGeometry {
+	return GeoGeometry{Type: "envelope", Coordinates: [][]float64{{topLeft.Lon, topLeft.Lat}, {bottomRight.Lon, bottomRight.Lat}}}
+}
+
+// GeoPolygonShape polygon shape, first and last point is closed automatically
+func GeoPolygonShape(points ...GeoPoint) GeoGeometry {
+	ring := make([][]float64, 0, len(points)+1)
+	for _, point := range points {
+		ring = append(ring, []float64{point.Lon, point.Lat})
+	}
+	if len(points) > 0 && points[0] != points[len(points)-1] {
+		ring = append(ring, []float64{points[0].Lon, points[0].Lat})
+	}
+	return GeoGeometry{Type: "polygon", Coordinates: [][][]float64{ring}}
+}
+
+func GeoPointShape(point GeoPoint) GeoGeometry {
+	return GeoGeometry{Type: "point", Coordinates: []float64{point.Lon, point.Lat}}
+}
+
+// geoQuery geo query, field entry and query options share one object, field name same as option is error
+type geoQuery struct {
+	kind   string
+	field  string
+	value  interface{}
+	params map[string]interface{}
+}
+
+func (g geoQuery) MarshalJSON() ([]byte, error) {
+	if _, ok := g.params[g.field]; ok {
+		return nil, fmt.Errorf("%s query error. field %s conflict with option of same name", g.kind, g.field)
+	}
+	body := make(map[string]interface{}, len(g.params)+1)
+	for key, val := range g.params {
+		body[key] = val
+	}
+	body[g.field] = g.value
+	return json.Marshal(map[string]interface{}{g.kind: body})
+}
+
+// GeoDistance document within distance of point. distance eg: 10km, 200m
+func (f filter) GeoDistance(field string, lat, lon float64, distance string) Filter {
+	params := map[string]interface{}{"distance": distance}
+	f.condition = append(f.condition, geoQuery{kind: "geo_distance", field: field, value: NewGeoPoint(lat, lon), params: params})
+	return f
+}
+
+func (f filter) GeoBoundingBox(field string, topLeft, bottomRight GeoPoint) Filter {
+	value := map[string]GeoPoint{"top_left": topLeft, "bottom_right": bottomRight}
+	f.condition = append(f.condition, geoQuery{kind: "geo_bounding_box", field: field, value: value})
+	return f
+}
+
+func (f filter) GeoPolygon(field string, points ...GeoPoint) Filter {
+	value := map[string][]GeoPoint{"points": points}
+	f.condition = append(f.condition, geoQuery{kind: "geo_polygon", field: field, value: value})
+	return f
+}
+
+// GeoShape geo_shape or geo_point field has relation with shape, empty relation is INTERSECTS
+func (f filter) GeoShape(field string, shape GeoGeometry, relation GeoShapeRelation) Filter {
+	value := map[string]interface{}{"shape": shape}
+	if relation != "" {
+		value["relation"] = relation
+	}
+	f.condition = append(f.condition, geoQuery{kind: "geo_shape", field: field, value: value})
+	return f
+}
@@ -0,0 +1,82 @@
+package ges
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		join field query. has_child, has_parent, parent_id
+
+***************************/
+
+// JoinField value of join field. parent document only has Name, child document has Name and Parent
+type JoinField struct {
+	Name   string `json:"name"`
+	Parent string `json:"parent,omitempty"`
+}
+
+// InnerHits return matched child, parent or nested documents with hit
+type InnerHits struct {
+	// Name key of inner hits in response, default is child type, parent type or nested path
+	Name   string        `json:"name,omitempty"`
+	From   int           `json:"from,omitempty"`
+	Size   int           `json:"size,omitempty"`
+	Sort   []interface{} `json:"sort,omitempty"`
+	Source []string      `json:"_source,omitempty"`
+}
+
+// JoinOptions options of has_child, has_parent, zero value not send
+type JoinOptions struct {
+	// ScoreMode has_child none, avg, sum, max, min
+	ScoreMode string
+	// Score has_parent use score of parent document
+	Score          bool
+	MinChildren    int
+	MaxChildren    int
+	IgnoreUnmapped bool
+	InnerHits      *InnerHits
+}
+
+func (o JoinOptions) params(keys ...string) map[string]interface{} {
+	all := map[string]interface{}{
+		"score_mode":      o.ScoreMode,
+		"score":           o.Score,
+		"min_children":    o.MinChildren,
+		"max_children":    o.MaxChildren,
+		"ignore_unmapped": o.IgnoreUnmapped,
+	}
+	result := pickParams(all, keys...)
+	if o.InnerHits != nil {
+		result["inner_hits"] = o.InnerHits
+	}
+	return result
+}
+
+func firstJoinOptions(opts []JoinOptions) JoinOptions {
+	if len(opts) == 0 {
+		return JoinOptions{}
+	}
+	return opts[0]
+}
+
+// HasChild parent documents which child documents of childType match query
+func (f filter) HasChild(childType string, query Filter, opts ...JoinOptions) Filter {
+	value := firstJoinOptions(opts).params("score_mode", "min_children", "max_children", "ignore_unmapped")
+	value["type"], value["query"] = childType, singleQuery(query)
+	f.condition = append(f.condition, map[string]interface{}{"has_child": value})
+	return f
+}
+
+// HasParent child documents which parent document of parentType match query
+func (f filter) HasParent(parentType string, query Filter, opts ...JoinOptions) Filter {
+	value := firstJoinOptions(opts).params("score", "ignore_unmapped")
+	value["parent_type"], value["query"] = parentType, singleQuery(query)
+	f.condition = append(f.condition, map[string]interface{}{"has_parent": value})
+	return f
+}
+
+// ParentID child documents of childType belong to parent id
+func (f filter) ParentID(childType, id string) Filter {
+	value := map[string]interface{}{"type": childType, "id": id}
+	f.condition = append(f.condition, map[string]interface{}{"parent_id": value})
+	return f
+}
@@ -0,0 +1,94 @@
+package ges
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		more_like_this query
+
+***************************/
+
+// DocRef document of more_like_this like/unlike. Id for indexed document, Doc for artificial document not indexed
+type DocRef struct {
+	Index   string      `json:"_index,omitempty"`
+	Id      string      `json:"_id,omitempty"`
+	Doc     interface{} `json:"doc,omitempty"`
+	Routing string      `json:"routing,omitempty"`
+}
+
+// MoreLikeThisOptions options of more_like_this, zero value and nil not send
+type MoreLikeThisOptions struct {
+	// MinTermFreq min frequency of term in input, elasticsearch default 2. pointer for sending 0
+	MinTermFreq *int
+	// MaxQueryTerms max terms selected from input, elasticsearch default 25
+	MaxQueryTerms int
+	// MinDocFreq min number of documents term appears, elasticsearch default 5. pointer for sending 0
+	MinDocFreq    *int
+	MaxDocFreq    int
+	MinWordLength int
+	MaxWordLength int
+	// MinimumShouldMatch number of selected terms must match, elasticsearch default 30%
+	MinimumShouldMatch string
+	StopWords          []string
+	Analyzer           string
+	Boost              float64
+	// Include input documents in result
+	Include     bool
+	UnlikeTexts []string
+	UnlikeDocs  []DocRef
+}
+
+func (o MoreLikeThisOptions) params() map[string]interface{} {
+	all := map[string]interface{}{
+		"min_term_freq":        o.MinTermFreq,
+		"max_query_terms":      o.MaxQueryTerms,
+		"min_doc_freq":         o.MinDocFreq,
+		"max_doc_freq":         o.MaxDocFreq,
+		"min_word_length":      o.MinWordLength,
+		"max_word_length":      o.MaxWordLength,
+		"minimum_should_match": o.MinimumShouldMatch,
+		"stop_words":           o.StopWords,
+		"analyzer":             o.Analyzer,
+		"boost":                o.Boost,
+		"include":              o.Include,
+	}
+	result := pickParams(all, "min_term_freq", "max_query_terms", "min_doc_freq", "max_doc_freq", "min_word_length",
+		"max_word_length", "minimum_should_match", "stop_words", "analyzer", "boost", "include")
+	if unlike := likeItems(o.UnlikeTexts, o.UnlikeDocs); len(unlike) != 0 {
+		result["unlike"] = unlike
+	}
+	return result
+}
+
+// likeItems texts and documents of like, unlike
+func likeItems(texts []string, docs []DocRef) []interface{} {
+	items := make([]interface{}, 0, len(texts)+len(docs))
+	for _, text := range texts {
+		items = append(items, text)
+	}
+	for _, doc := range docs {
+		items = append(items, doc)
+	}
+	return items
+}
+
+// MoreLikeThis documents similar to likeTexts and likeDocs, fields empty use index.query.default_field.
+// likeTexts and likeDocs both empty is match_none, nothing is similar to nothing
+func (f filter) MoreLikeThis(fields []string, likeTexts []string, likeDocs []DocRef, opts ...MoreLikeThisOptions) Filter {
+	like := likeItems(likeTexts, likeDocs)
+	if len(like) == 0 {
+		f.condition = append(f.condition, map[string]interface{}{"match_none": map[string]interface{}{}})
+		return f
+	}
+	opt := MoreLikeThisOptions{}
+	if len(opts) != 0 {
+		opt = opts[0]
+	}
+	value := opt.params()
+	value["like"] = like
+	if len(fields) != 0 {
+		value["fields"] = fields
+	}
+	f.condition = append(f.condition, map[string]interface{}{"more_like_this": value})
+	return f
+}
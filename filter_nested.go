@@ -10,6 +10,10 @@ package ges
 type esNested struct {
 	NestedPath string        `json:"path"`
 	Query      esNestedQuery `json:"query"`
+	// ScoreModeVal avg, max, min, sum, none
+	ScoreModeVal      string     `json:"score_mode,omitempty"`
+	IgnoreUnmappedVal bool       `json:"ignore_unmapped,omitempty"`
+	InnerHitsVal      *InnerHits `json:"inner_hits,omitempty"`
 }
 
 type esNestedQuery struct {
@@ -18,6 +22,9 @@ type esNestedQuery struct {
 
 func (e esNested) Must(filters ...Filter) NestedFilter {
 	for _, filter := range filters {
+		if filter == nil {
+			continue
+		}
 		e.Query.Bool.Must = append(e.Query.Bool.Must, filter.Result()...)
 	}
 	return e
@@ -25,6 +32,9 @@ func (e esNested) Must(filters ...Filter) NestedFilter {
 
 func (e esNested) Should(filters ...Filter) NestedFilter {
 	for _, filter := range filters {
+		if filter == nil {
+			continue
+		}
 		e.Query.Bool.Should = append(e.Query.Bool.Should, filter.Result()...)
 	}
 	return e
@@ -32,18 +42,59 @@ func (e esNested) Should(filters ...Filter) NestedFilter {
 
 func (e esNested) Not(filters ...Filter) NestedFilter {
 	for _, filter := range filters {
+		if filter == nil {
+			continue
+		}
 		e.Query.Bool.Not = append(e.Query.Bool.Not, filter.Result()...)
 	}
 	return e
 }
 
+// Filter filter context of nested query, conditions not affect score
+func (e esNested) Filter(filters ...Filter) NestedFilter {
+	for _, filter := range filters {
+		if filter == nil {
+			continue
+		}
+		e.Query.Bool.Filter = append(e.Query.Bool.Filter, filter.Result()...)
+	}
+	return e
+}
+
 func (e esNested) Match(filters ...Filter) NestedFilter {
 	for _, filter := range filters {
+		if filter == nil {
+			continue
+		}
 		e.Query.Bool.Match = append(e.Query.Bool.Match, filter.Result()...)
 	}
 	return e
 }
 
+// MinimumShouldMatch number or percentage of Should conditions must match
+func (e esNested) MinimumShouldMatch(minimumShouldMatch string) NestedFilter {
+	e.Query.Bool.MinimumShouldMatch = minimumShouldMatch
+	return e
+}
+
+// ScoreMode how scores of matching nested objects affect score of root document. avg, max, min, sum, none
+func (e esNested) ScoreMode(scoreMode string) NestedFilter {
+	e.ScoreModeVal = scoreMode
+	return e
+}
+
+// IgnoreUnmapped no error when path not mapped, query match nothing
+func (e esNested) IgnoreUnmapped(ignoreUnmapped bool) NestedFilter {
+	e.IgnoreUnmappedVal = ignoreUnmapped
+	return e
+}
+
+// InnerHits return matched nested objects with hit, get by SearchWithInnerHits
+func (e esNested) InnerHits(innerHits InnerHits) NestedFilter {
+	e.InnerHitsVal = &innerHits
+	return e
+}
+
 func (e esNested) Path(path string) NestedFilter {
 	e.NestedPath = path
 	return e
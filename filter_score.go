@@ -0,0 +1,235 @@
+package ges
+
+import (
+	"encoding/json"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		relevance score query. function_score, script_score
+
+***************************/
+
+// DecayType decay function of function_score
+type DecayType string
+
+const (
+	DecayGauss  DecayType = "gauss"
+	DecayExp    DecayType = "exp"
+	DecayLinear DecayType = "linear"
+)
+
+// FieldValueModifier modifier applied to field value of field_value_factor
+type FieldValueModifier string
+
+const (
+	FieldValueNone       FieldValueModifier = "none"
+	FieldValueLog        FieldValueModifier = "log"
+	FieldValueLog1p      FieldValueModifier = "log1p"
+	FieldValueLog2p      FieldValueModifier = "log2p"
+	FieldValueLn         FieldValueModifier = "ln"
+	FieldValueLn1p       FieldValueModifier = "ln1p"
+	FieldValueLn2p       FieldValueModifier = "ln2p"
+	FieldValueSquare     FieldValueModifier = "square"
+	FieldValueSqrt       FieldValueModifier = "sqrt"
+	FieldValueReciprocal FieldValueModifier = "reciprocal"
+)
+
+// DecayOptions options of decay function, zero value not send
+type DecayOptions struct {
+	// Offset distance from origin score is 1. eg: 1d, 2km
+	Offset string
+	// Decay score at scale distance, elasticsearch default 0.5
+	Decay float64
+	// MultiValueMode min, max, avg, sum. field has multi values
+	MultiValueMode string
+}
+
+// FieldValueFactorOptions options of field_value_factor, zero value not send
+type FieldValueFactorOptions struct {
+	Factor   float64
+	Modifier FieldValueModifier
+	// Missing value used when document has no field, nil return error by elasticsearch
+	Missing *float64
+}
+
+// FunctionScoreOptions options of function_score, zero value not send
+type FunctionScoreOptions struct {
+	// ScoreMode how function scores combined. multiply, sum, avg, first, max, min
+	ScoreMode string
+	// BoostMode how function score combined with query score. multiply, replace, sum, avg, max, min
+	BoostMode string
+	MaxBoost  float64
+	MinScore  float64
+	Boost     float64
+}
+
+func (o FunctionScoreOptions) params() map[string]interface{} {
+	all := map[string]interface{}{
+		"score_mode": o.ScoreMode,
+		"boost_mode": o.BoostMode,
+		"max_boost":  o.MaxBoost,
+		"min_score":  o.MinScore,
+		"boost":      o.Boost,
+	}
+	return pickParams(all, "score_mode", "boost_mode", "max_boost", "min_score", "boost")
+}
+
+// scoreFunction function of function_score, kind empty is weight function
+type scoreFunction struct {
+	kind   string
+	value  interface{}
+	filter Filter
+	weight float64
+}
+
+// DecayFunction score decay with distance from origin. origin is number, date or GeoPoint. scale eg: 10d, 5km
+func DecayFunction(decay DecayType, field string, origin interface{}, scale string, opts ...DecayOptions) ScoreFunction {
+	opt := DecayOptions{}
+	if len(opts) != 0 {
+		opt = opts[0]
+	}
+	fieldValue := pickParams(map[string]interface{}{"offset": opt.Offset, "decay": opt.Decay}, "offset", "decay")
+	fieldValue["origin"], fieldValue["scale"] = rangeValue(origin), scale
+	value := map[string]interface{}{field: fieldValue}
+	if opt.MultiValueMode != "" {
+		value["multi_value_mode"] = opt.MultiValueMode
+	}
+	return scoreFunction{kind: string(decay), value: value}
+}
+
+func GaussDecay(field string, origin interface{}, scale string, opts ...DecayOptions) ScoreFunction {
+	return DecayFunction(DecayGauss, field, origin, scale, opts...)
+}
+
+func ExpDecay(field string, origin interface{}, scale string, opts ...DecayOptions) ScoreFunction {
+	return DecayFunction(DecayExp, field, origin, scale, opts...)
+}
+
+func LinearDecay(field string, origin interface{}, scale string, opts ...DecayOptions) ScoreFunction {
+	return DecayFunction(DecayLinear, field, origin, scale, opts...)
+}
+
+// FieldValueFactor score from numeric field value
+func FieldValueFactor(field string, opts ...FieldValueFactorOptions) ScoreFunction {
+	opt := FieldValueFactorOptions{}
+	if len(opts) != 0 {
+		opt = opts[0]
+	}
+	value := pickParams(map[string]interface{}{"factor": opt.Factor, "modifier": opt.Modifier}, "factor", "modifier")
+	value["field"] = field
+	if opt.Missing != nil {
+		value["missing"] = *opt.Missing
+	}
+	return scoreFunction{kind: "field_value_factor", value: value}
+}
+
+// WeightFunction constant score weight, used with Filter of ScoreFunction
+func WeightFunction(weight float64) ScoreFunction {
+	return scoreFunction{weight: weight}
+}
+
+// RandomScore random score, same seed and field get same score. seed nil score is not reproducible.
+// field empty use _seq_no by elasticsearch
+func RandomScore(seed interface{}, field string) ScoreFunction {
+	value := map[string]interface{}{}
+	if seed != nil {
+		value["seed"] = seed
+	}
+	if field != "" {
+		value["field"] = field
+	}
+	return scoreFunction{kind: "random_score", value: value}
+}
+
+// ScriptScoreFunction score calculated by script. eg: _score * doc['likes'].value
+func ScriptScoreFunction(script Script) ScoreFunction {
+	return scoreFunction{kind: "script_score", value: map[string]interface{}{"script": script}}
+}
+
+// Filter function only apply to documents match filter
+func (s scoreFunction) Filter(filter Filter) ScoreFunction {
+	s.filter = filter
+	return s
+}
+
+func (s scoreFunction) Weight(weight float64) ScoreFunction {
+	s.weight = weight
+	return s
+}
+
+func (s scoreFunction) Result() map[string]interface{} {
+	result := make(map[string]interface{}, 3)
+	if s.kind != "" {
+		result[s.kind] = s.value
+	}
+	if s.filter != nil && len(s.filter.Result()) != 0 {
+		result["filter"] = singleQuery(s.filter)
+	}
+	if s.weight != 0 {
+		result["weight"] = s.weight
+	}
+	return result
+}
+
+// singleQuery filter as one query. empty is match_all, multi conditions is bool must
+func singleQuery(f Filter) interface{} {
+	if f == nil || len(f.Result()) == 0 {
+		return map[string]map[string]interface{}{"match_all": {}}
+	}
+	conditions := f.Result()
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return boolFilter{Bool: esQueryBool{Must: conditions}}
+}
+
+type functionScore struct {
+	query     Filter
+	opts      FunctionScoreOptions
+	functions []ScoreFunction
+}
+
+func (fs functionScore) MarshalJSON() ([]byte, error) {
+	value := fs.opts.params()
+	value["query"] = singleQuery(fs.query)
+	functions := make([]map[string]interface{}, 0, len(fs.functions))
+	for _, item := range fs.functions {
+		if item == nil {
+			continue
+		}
+		functions = append(functions, item.Result())
+	}
+	if len(functions) != 0 {
+		value["functions"] = functions
+	}
+	return json.Marshal(map[string]interface{}{"function_score": value})
+}
+
+// FunctionScore modify score of documents match query by functions, query nil is match_all
+func (f filter) FunctionScore(query Filter, functions ...ScoreFunction) Filter {
+	return f.FunctionScoreWith(query, FunctionScoreOptions{}, functions...)
+}
+
+// FunctionScoreWith function_score with options. eg: score_mode, boost_mode, max_boost
+func (f filter) FunctionScoreWith(query Filter, opts FunctionScoreOptions, functions ...ScoreFunction) Filter {
+	f.condition = append(f.condition, functionScore{query: query, opts: opts, functions: functions})
+	return f
+}
+
+// ScriptScore score of documents match query calculated by script, query nil is match_all
+func (f filter) ScriptScore(query Filter, script Script, minScore ...float64) Filter {
+	value := map[string]interface{}{
+		"query":  singleQuery(query),
+		"script": script,
+	}
+	if len(minScore) != 0 {
+		value["min_score"] = minScore[0]
+	}
+	f.condition = append(f.condition, map[string]interface{}{"script_score": value})
+	return f
+}
+
+var _ ScoreFunction = (*scoreFunction)(nil)
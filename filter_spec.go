@@ -0,0 +1,369 @@
+package ges
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		json filter spec of frontend to Filter, fields, operators and size limited by FilterSchema.
+		eg: {"and": [{"field": "status", "op": "in", "value": ["open", "closed"]}, {"not": {"field": "level", "op": "eq", "value": 1}}]}
+
+***************************/
+
+// FilterOp operator of FilterSpec leaf node
+type FilterOp string
+
+const (
+	FilterOpEq    FilterOp = "eq"
+	FilterOpNe    FilterOp = "ne"
+	FilterOpIn    FilterOp = "in"
+	FilterOpNotIn FilterOp = "not_in"
+	FilterOpGt    FilterOp = "gt"
+	FilterOpGte   FilterOp = "gte"
+	FilterOpLt    FilterOp = "lt"
+	FilterOpLte   FilterOp = "lte"
+	// FilterOpBetween value is [start, end], start <= field <= end
+	FilterOpBetween FilterOp = "between"
+	// FilterOpPrefix keyword field starts with value
+	FilterOpPrefix FilterOp = "prefix"
+	// FilterOpMatch full text match of text field
+	FilterOpMatch FilterOp = "match"
+	// FilterOpExists field has value, value is ignored
+	FilterOpExists FilterOp = "exists"
+	// FilterOpMissing field has no value, value is ignored
+	FilterOpMissing FilterOp = "missing"
+)
+
+const (
+	DefaultFilterSpecMaxDepth  = 8
+	DefaultFilterSpecMaxNodes  = 100
+	DefaultFilterSpecMaxValues = 1000
+)
+
+// FilterSpec node of json filter. group node has one of And, Or, Not, leaf node has Field, Op and Value.
+// Nested of group node is nested path, conditions of group must match in same nested object
+type FilterSpec struct {
+	And    []FilterSpec `json:"and,omitempty"`
+	Or     []FilterSpec `json:"or,omitempty"`
+	Not    *FilterSpec  `json:"not,omitempty"`
+	Nested string       `json:"nested,omitempty"`
+
+	Field string      `json:"field,omitempty"`
+	Op    FilterOp    `json:"op,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// FilterSchema allowed fields and limits of FilterSpec, zero limit use default
+type FilterSchema struct {
+	Fields FieldSchema
+	// MaxDepth max depth of group node
+	MaxDepth int
+	// MaxNodes max number of nodes
+	MaxNodes int
+	// MaxValues max number of values of in, not_in
+	MaxValues int
+}
+
+// FilterSpecError invalid node of FilterSpec, Path is location of node. eg: and[1].not
+type FilterSpecError struct {
+	Path string
+	Msg  string
+}
+
+func (e *FilterSpecError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("filter spec error: %s", e.Msg)
+	}
+	return fmt.Sprintf("filter spec error at %s: %s", e.Path, e.Msg)
+}
+
+// filterSpecOps operators allowed by field type
+var filterSpecOps = map[string][]FilterOp{
+	"keyword": {FilterOpEq, FilterOpNe, FilterOpIn, FilterOpNotIn, FilterOpPrefix, FilterOpExists, FilterOpMissing},
+	"text":    {FilterOpMatch, FilterOpExists, FilterOpMissing},
+	"range": {FilterOpEq, FilterOpNe, FilterOpIn, FilterOpNotIn, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte,
+		FilterOpBetween, FilterOpExists, FilterOpMissing},
+	"boolean": {FilterOpEq, FilterOpNe, FilterOpExists, FilterOpMissing},
+}
+
+type filterSpecCompiler struct {
+	schema FilterSchema
+	nodes  int
+}
+
+// CompileFilter FilterSpec to Filter, field not in schema, operator not allowed by field type,
+// value not match field type and spec exceeding limits is *FilterSpecError
+func CompileFilter(spec FilterSpec, schema FilterSchema) (Filter, error) {
+	if schema.MaxDepth <= 0 {
+		schema.MaxDepth = DefaultFilterSpecMaxDepth
+	}
+	if schema.MaxNodes <= 0 {
+		schema.MaxNodes = DefaultFilterSpecMaxNodes
+	}
+	if schema.MaxValues <= 0 {
+		schema.MaxValues = DefaultFilterSpecMaxValues
+	}
+	c := &filterSpecCompiler{schema: schema}
+	item, err := c.compile(spec, "", "", 0)
+	if err != nil {
+		return nil, err
+	}
+	return schemaFilter(item), nil
+}
+
+// CompileFilterJSON json of FilterSpec to Filter, number decoded as json.Number to keep precision of long
+func CompileFilterJSON(data []byte, schema FilterSchema) (Filter, error) {
+	spec := FilterSpec{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, &FilterSpecError{Msg: fmt.Sprintf("json decode error. %s", err.Error())}
+	}
+	return CompileFilter(spec, schema)
+}
+
+func (c *filterSpecCompiler) errorf(path, format string, args ...interface{}) error {
+	return &FilterSpecError{Path: path, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (c *filterSpecCompiler) compile(spec FilterSpec, path, nestedPath string, depth int) (interface{}, error) {
+	c.nodes++
+	if c.nodes > c.schema.MaxNodes {
+		return nil, c.errorf(path, "more than %d nodes", c.schema.MaxNodes)
+	}
+	kinds := 0
+	for _, has := range []bool{spec.And != nil, spec.Or != nil, spec.Not != nil, spec.Field != ""} {
+		if has {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return nil, c.errorf(path, "node must have exactly one of and, or, not, field")
+	}
+	if spec.Field != "" {
+		if spec.Nested != "" {
+			return nil, c.errorf(path, "nested only for and, or, not")
+		}
+		return c.compileLeaf(spec, path, nestedPath)
+	}
+
+	if depth >= c.schema.MaxDepth {
+		return nil, c.errorf(path, "deeper than %d levels", c.schema.MaxDepth)
+	}
+	innerPath := nestedPath
+	if spec.Nested != "" {
+		fieldType, err := c.schema.Fields.fieldType(spec.Nested)
+		if err != nil || fieldType != MappingTypeNested {
+			return nil, c.errorf(path, "nested %s is not allowed nested field", spec.Nested)
+		}
+		innerPath = spec.Nested
+	}
+
+	var item interface{}
+	switch {
+	case spec.Not != nil:
+		child, err := c.compile(*spec.Not, joinSpecPath(path, "not"), innerPath, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		item = boolFilter{Bool: esQueryBool{Not: []interface{}{child}}}
+	default:
+		name, children := "and", spec.And
+		if spec.Or != nil {
+			name, children = "or", spec.Or
+		}
+		if len(children) == 0 {
+			return nil, c.errorf(path, "%s is empty", name)
+		}
+		items := make([]interface{}, 0, len(children))
+		for idx, child := range children {
+			childItem, err := c.compile(child, joinSpecPath(path, name+"["+strconv.Itoa(idx)+"]"), innerPath, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, childItem)
+		}
+		if name == "and" {
+			item = boolFilter{Bool: esQueryBool{Must: items}}
+		} else {
+			item = boolFilter{Bool: esQueryBool{Should: items, MinimumShouldMatch: "1"}}
+		}
+	}
+	if spec.Nested == "" || spec.Nested == nestedPath {
+		return item, nil
+	}
+	nested := esNested{NestedPath: spec.Nested}
+	nested.Query.Bool.Must = []interface{}{item}
+	return c.schema.Fields.wrapNested(nestedPath, spec.Nested, nested.Result()), nil
+}
+
+func joinSpecPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func filterSpecTypeGroup(fieldType MappingType) string {
+	switch {
+	case fieldType == MappingTypeKeyword:
+		return "keyword"
+	case fieldType == MappingTypeText:
+		return "text"
+	case fieldType == MappingTypeBoolean:
+		return "boolean"
+	case isRangeMappingType(fieldType):
+		return "range"
+	}
+	return ""
+}
+
+func (c *filterSpecCompiler) compileLeaf(spec FilterSpec, path, nestedPath string) (interface{}, error) {
+	field := spec.Field
+	fieldType, err := c.schema.Fields.fieldType(field)
+	if err != nil {
+		return nil, c.errorf(path, "%s", err.Error())
+	}
+	if nestedPath != "" && !strings.HasPrefix(field, nestedPath+".") {
+		return nil, c.errorf(path, "field %s not in nested %s", field, nestedPath)
+	}
+	allowed := false
+	for _, op := range filterSpecOps[filterSpecTypeGroup(fieldType)] {
+		if op == spec.Op {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, c.errorf(path, "op %s not allowed for field %s of type %s", spec.Op, field, fieldType)
+	}
+
+	var item interface{}
+	switch spec.Op {
+	case FilterOpExists:
+		item = exists{name: field}
+	case FilterOpMissing:
+		item = boolFilter{Bool: esQueryBool{Not: []interface{}{exists{name: field}}}}
+	case FilterOpIn, FilterOpNotIn, FilterOpBetween:
+		values, err := c.specValues(spec, path, fieldType)
+		if err != nil {
+			return nil, err
+		}
+		switch spec.Op {
+		case FilterOpIn:
+			item = terms{name: field, values: values}
+		case FilterOpNotIn:
+			item = boolFilter{Bool: esQueryBool{Not: []interface{}{terms{name: field, values: values}}}}
+		default:
+			if len(values) != 2 {
+				return nil, c.errorf(path, "between value must be [start, end]")
+			}
+			b := between{name: field}
+			b.GtePtr, b.LtePtr = rangeValue(values[0]), rangeValue(values[1])
+			item = b
+		}
+	default:
+		value, err := specValue(field, fieldType, spec.Value)
+		if err != nil {
+			return nil, c.errorf(path, "%s", err.Error())
+		}
+		switch spec.Op {
+		case FilterOpEq:
+			item = term{name: field, value: value}
+		case FilterOpNe:
+			item = boolFilter{Bool: esQueryBool{Not: []interface{}{term{name: field, value: value}}}}
+		case FilterOpPrefix:
+			item = paramQuery{kind: "prefix", field: field, params: map[string]interface{}{"value": value}}
+		case FilterOpMatch:
+			item = match{name: field, value: value}
+		case FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte:
+			item = schemaRange(field, string(spec.Op), value)
+		}
+	}
+	return c.schema.Fields.wrapNested(nestedPath, field, item), nil
+}
+
+func (c *filterSpecCompiler) specValues(spec FilterSpec, path string, fieldType MappingType) ([]interface{}, error) {
+	v := reflect.ValueOf(spec.Value)
+	if spec.Value == nil || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) {
+		return nil, c.errorf(path, "op %s value must be array", spec.Op)
+	}
+	if v.Len() == 0 {
+		return nil, c.errorf(path, "op %s value is empty", spec.Op)
+	}
+	if v.Len() > c.schema.MaxValues {
+		return nil, c.errorf(path, "op %s more than %d values", spec.Op, c.schema.MaxValues)
+	}
+	values := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		value, err := specValue(spec.Field, fieldType, v.Index(i).Interface())
+		if err != nil {
+			return nil, c.errorf(path, "%s", err.Error())
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// specValue json value converted to field type. date accept string and epoch millis
+func specValue(field string, fieldType MappingType, value interface{}) (interface{}, error) {
+	switch val := value.(type) {
+	case nil:
+		return nil, fmt.Errorf("field %s value is null, use op exists or missing", field)
+	case string:
+		return schemaValue(field, fieldType, val)
+	case bool:
+		if fieldType != MappingTypeBoolean {
+			return nil, fmt.Errorf("field %s is %s, value %v invalid", field, fieldType, val)
+		}
+		return val, nil
+	case json.Number:
+		switch {
+		case fieldType == MappingTypeDate:
+			if n, err := val.Int64(); err == nil {
+				return n, nil
+			}
+			f, err := val.Float64()
+			if err != nil {
+				return nil, fmt.Errorf("field %s is %s, value %v invalid", field, fieldType, val)
+			}
+			return int64(f), nil
+		case !isNumericMappingType(fieldType):
+			return nil, fmt.Errorf("field %s is %s, value %v invalid", field, fieldType, val)
+		}
+		return schemaValue(field, fieldType, val.String())
+	case float64:
+		switch {
+		case fieldType == MappingTypeDate:
+			return int64(val), nil
+		case !isNumericMappingType(fieldType):
+			return nil, fmt.Errorf("field %s is %s, value %v invalid", field, fieldType, val)
+		}
+		if _, err := schemaValue(field, fieldType, strconv.FormatFloat(val, 'f', -1, 64)); err != nil {
+			return nil, err
+		}
+		if val == math.Trunc(val) && math.Abs(val) < 1<<53 {
+			return int64(val), nil
+		}
+		return val, nil
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return specValue(field, fieldType, json.Number(strconv.FormatInt(v.Int(), 10)))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return specValue(field, fieldType, json.Number(strconv.FormatUint(v.Uint(), 10)))
+	case reflect.Float32:
+		return specValue(field, fieldType, v.Float())
+	}
+	return nil, fmt.Errorf("field %s value type %T not support", field, value)
+}
@@ -0,0 +1,78 @@
+package ges
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+
+***************************/
+
+var testFilterSchema = FilterSchema{Fields: testKQLSchema}
+
+func TestCompileFilter(t *testing.T) {
+	f, err := CompileFilterJSON([]byte(`{"and":[`+
+		`{"field":"status","op":"in","value":["open","closed"]},`+
+		`{"field":"priority","op":"between","value":[1,3]},`+
+		`{"not":{"field":"tags","op":"eq","value":"spam"}},`+
+		`{"or":[{"field":"title","op":"match","value":"quick fox"},{"field":"tags","op":"missing"}]}]}`), testFilterSchema)
+	require.NoError(t, err, "TestCompileFilter CompileFilterJSON")
+	actual, err := json.Marshal(f.Result())
+	require.NoError(t, err, "TestCompileFilter json.Marshal")
+	expected := `[{"terms":{"status":["open","closed"]}},{"range":{"priority":{"lte":3,"gte":1}}},` +
+		`{"bool":{"must_not":[{"term":{"tags":"spam"}}]}},` +
+		`{"bool":{"should":[{"match":{"title":"quick fox"}},{"bool":{"must_not":[{"exists":{"field":"tags"}}]}}],"minimum_should_match":"1"}}]`
+	require.Equal(t, expected, string(actual), "TestCompileFilter")
+
+	f, err = CompileFilter(FilterSpec{Nested: "items", And: []FilterSpec{
+		{Field: "items.sku", Op: FilterOpPrefix, Value: "a"},
+		{Field: "items.qty", Op: FilterOpGt, Value: 1},
+	}}, testFilterSchema)
+	require.NoError(t, err, "TestCompileFilter CompileFilter nested")
+	actual, err = json.Marshal(f.Result())
+	require.NoError(t, err, "TestCompileFilter json.Marshal")
+	expected = `[{"nested":{"path":"items","query":{"bool":{"must":[{"bool":{"must":[` +
+		`{"prefix":{"items.sku":{"value":"a"}}},{"range":{"items.qty":{"gt":1}}}]}}]}}}}]`
+	require.Equal(t, expected, string(actual), "TestCompileFilter nested")
+
+	// long value beyond 2^53 keep precision
+	f, err = CompileFilterJSON([]byte(`{"field":"items.qty","op":"eq","value":9007199254740993}`), testFilterSchema)
+	require.NoError(t, err, "TestCompileFilter CompileFilterJSON long")
+	actual, err = json.Marshal(f.Result())
+	require.NoError(t, err, "TestCompileFilter json.Marshal")
+	expected = `[{"nested":{"path":"items","query":{"bool":{"must":[{"term":{"items.qty":9007199254740993}}]}}}}]`
+	require.Equal(t, expected, string(actual), "TestCompileFilter long")
+}
+
+func TestCompileFilterError(t *testing.T) {
+	deep := FilterSpec{Field: "status", Op: FilterOpEq, Value: "open"}
+	for i := 0; i < 3; i++ {
+		deep = FilterSpec{Not: &deep}
+	}
+	for name, item := range map[string]struct {
+		spec FilterSpec
+		path string
+	}{
+		"field not allowed":  {FilterSpec{Field: "password", Op: FilterOpEq, Value: "1"}, ""},
+		"op not allowed":     {FilterSpec{And: []FilterSpec{{Field: "title", Op: FilterOpEq, Value: "a"}}}, "and[0]"},
+		"value type":         {FilterSpec{Or: []FilterSpec{{Field: "priority", Op: FilterOpEq, Value: "high"}}}, "or[0]"},
+		"in not array":       {FilterSpec{Field: "status", Op: FilterOpIn, Value: "open"}, ""},
+		"too many values":    {FilterSpec{Field: "status", Op: FilterOpIn, Value: []string{"a", "b", "c"}}, ""},
+		"empty group":        {FilterSpec{And: []FilterSpec{}}, ""},
+		"both group field":   {FilterSpec{Field: "status", Op: FilterOpExists, Not: &FilterSpec{}}, ""},
+		"too deep":           {deep, "not.not"},
+		"outside nested":     {FilterSpec{Nested: "items", Not: &FilterSpec{Field: "status", Op: FilterOpExists}}, "not"},
+		"nested not allowed": {FilterSpec{Nested: "status", Not: &FilterSpec{Field: "status", Op: FilterOpExists}}, ""},
+	} {
+		_, err := CompileFilter(item.spec, FilterSchema{Fields: testKQLSchema, MaxDepth: 2, MaxValues: 2})
+		specErr := &FilterSpecError{}
+		require.True(t, errors.As(err, &specErr), "TestCompileFilterError "+name)
+		require.Equal(t, item.path, specErr.Path, "TestCompileFilterError "+name)
+	}
+}
@@ -0,0 +1,206 @@
+package ges
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		query by example, struct fields with ges tag to Filter.
+		eg: Status string `ges:"status,term"`, Tags []string `ges:"tags,terms"`, CreatedAt time.Time `ges:"created_at,gte"`
+
+***************************/
+
+// StructTagName tag name of FilterFromStruct, format: field,op[,keepzero]
+const StructTagName = "ges"
+
+// operator of struct tag
+const (
+	StructOpTerm           = "term"
+	StructOpTerms          = "terms"
+	StructOpNotTerm        = "not_term"
+	StructOpNotTerms       = "not_terms"
+	StructOpMatch          = "match"
+	StructOpMatchPhrase    = "match_phrase"
+	StructOpPrefix         = "prefix"
+	StructOpWildcard       = "wildcard"
+	StructOpWildcardSuffix = "wildcard_suffix"
+	StructOpGt             = "gt"
+	StructOpGte            = "gte"
+	StructOpLt             = "lt"
+	StructOpLte            = "lte"
+	// StructOpExists bool value, true is exists, false is missing
+	StructOpExists = "exists"
+	StructOpIds    = "ids"
+)
+
+// structTagKeepZero tag option, zero value not skipped
+const structTagKeepZero = "keepzero"
+
+// FilterFromStruct conditions of tagged fields in struct v, fields combined with and.
+// zero value and nil pointer are skipped, non-nil pointer and keepzero option are not skipped.
+// anonymous struct fields are flattened, field without tag or tag "-" is ignored.
+// unknown op or field type not match op is error, even if value is skipped
+func FilterFromStruct(v interface{}) (Filter, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("filter from struct error. nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("filter from struct error. %T not struct", v)
+	}
+	return structFilter(filter{}, rv)
+}
+
+func structFilter(f Filter, rv reflect.Value) (Filter, error) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf, fv := rt.Field(i), rv.Field(i)
+		tag, ok := sf.Tag.Lookup(StructTagName)
+		if sf.Anonymous && !ok {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() != reflect.Struct {
+				continue
+			}
+			var err error
+			if f, err = structFilter(f, fv); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !ok || tag == "-" || sf.PkgPath != "" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		if len(parts) < 2 || parts[0] == "" {
+			return nil, fmt.Errorf("filter from struct error. field %s tag %s need field,op", sf.Name, tag)
+		}
+		if err := structTagCheck(parts, sf.Type); err != nil {
+			return nil, fmt.Errorf("filter from struct error. field %s %s", sf.Name, err.Error())
+		}
+		keepZero := len(parts) > 2 && parts[2] == structTagKeepZero
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv, keepZero = fv.Elem(), true
+		}
+		if !keepZero && structValueIsZero(fv) {
+			continue
+		}
+		var err error
+		if f, err = structFieldFilter(f, parts[0], parts[1], fv); err != nil {
+			return nil, fmt.Errorf("filter from struct error. field %s %s", sf.Name, err.Error())
+		}
+	}
+	return f, nil
+}
+
+// structTagCheck op known and field type match op, checked before zero value skipped
+func structTagCheck(parts []string, t reflect.Type) error {
+	if len(parts) > 3 || (len(parts) == 3 && parts[2] != structTagKeepZero) {
+		return fmt.Errorf("tag option %s not support", strings.Join(parts[2:], ","))
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	op, kind := parts[1], t.Kind()
+	var need reflect.Kind
+	switch op {
+	case StructOpTerm, StructOpNotTerm, StructOpGt, StructOpGte, StructOpLt, StructOpLte, StructOpMatch:
+		return nil
+	case StructOpTerms, StructOpNotTerms, StructOpIds:
+		if kind == reflect.Slice || kind == reflect.Array {
+			return nil
+		}
+		need = reflect.Slice
+	case StructOpExists:
+		need = reflect.Bool
+	case StructOpMatchPhrase, StructOpPrefix, StructOpWildcard, StructOpWildcardSuffix:
+		need = reflect.String
+	default:
+		return fmt.Errorf("op %s not support", op)
+	}
+	// interface checked by value when filter built
+	if kind != need && kind != reflect.Interface {
+		return fmt.Errorf("op %s need %s, got %s", op, need, kind)
+	}
+	return nil
+}
+
+// structValueIsZero zero value or empty slice, map
+func structValueIsZero(fv reflect.Value) bool {
+	switch fv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return fv.Len() == 0
+	case reflect.Interface:
+		return fv.IsNil()
+	}
+	return fv.IsZero()
+}
+
+func structFieldFilter(f Filter, field, op string, fv reflect.Value) (Filter, error) {
+	value := fv.Interface()
+	switch op {
+	case StructOpTerm:
+		return f.Term(field, value), nil
+	case StructOpNotTerm:
+		return f.BoolItem(nil, filter{}.Term(field, value), nil, nil, false), nil
+	case StructOpTerms, StructOpNotTerms, StructOpIds:
+		if fv.Kind() != reflect.Slice && fv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("op %s need slice, got %s", op, fv.Kind())
+		}
+		switch op {
+		case StructOpTerms:
+			return f.Terms(field, value), nil
+		case StructOpNotTerms:
+			return f.BoolItem(nil, filter{}.Terms(field, value), nil, nil, false), nil
+		}
+		ids := make([]string, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			ids = append(ids, fmt.Sprint(fv.Index(i).Interface()))
+		}
+		return f.Ids(ids...), nil
+	case StructOpGt:
+		return f.Gt(field, value), nil
+	case StructOpGte:
+		return f.Gte(field, value), nil
+	case StructOpLt:
+		return f.Lt(field, value), nil
+	case StructOpLte:
+		return f.Lte(field, value), nil
+	case StructOpMatch:
+		return f.Match(field, value), nil
+	case StructOpExists:
+		if fv.Kind() != reflect.Bool {
+			return nil, fmt.Errorf("op %s need bool, got %s", op, fv.Kind())
+		}
+		if fv.Bool() {
+			return f.Exists(field), nil
+		}
+		return f.Missing(field), nil
+	case StructOpMatchPhrase, StructOpPrefix, StructOpWildcard, StructOpWildcardSuffix:
+		if fv.Kind() != reflect.String {
+			return nil, fmt.Errorf("op %s need string, got %s", op, fv.Kind())
+		}
+		switch op {
+		case StructOpMatchPhrase:
+			return f.MatchPhrase(field, fv.String()), nil
+		case StructOpPrefix:
+			return f.Prefix(field, fv.String()), nil
+		case StructOpWildcard:
+			return f.Wildcard(field, fv.String()), nil
+		}
+		return f.WildcardSuffix(field, fv.String()), nil
+	}
+	return nil, fmt.Errorf("op %s not support", op)
+}
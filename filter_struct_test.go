@@ -0,0 +1,76 @@
+package ges
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+
+***************************/
+
+type testStructPage struct {
+	Deleted *bool `ges:"deleted_at,exists"`
+}
+
+type testStructReq struct {
+	testStructPage
+	Status    string    `ges:"status,term"`
+	Level     int       `ges:"level,term,keepzero"`
+	Tags      []string  `ges:"tags,terms"`
+	Name      string    `ges:"name,wildcard_suffix"`
+	CreatedAt time.Time `ges:"created_at,gte"`
+	Score     *float64  `ges:"score,lt"`
+	Page      int       `ges:"-"`
+	Keyword   string
+}
+
+func TestFilterFromStruct(t *testing.T) {
+	deleted, score := false, 0.0
+	req := testStructReq{
+		testStructPage: testStructPage{Deleted: &deleted},
+		Tags:           []string{"a", "b"},
+		Name:           "jo",
+		CreatedAt:      time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		Score:          &score,
+		Page:           1,
+		Keyword:        "skip",
+	}
+	f, err := FilterFromStruct(&req)
+	require.NoError(t, err, "TestFilterFromStruct FilterFromStruct")
+	actual, err := json.Marshal(f.Result())
+	require.NoError(t, err, "TestFilterFromStruct json.Marshal")
+	expected := `[{"bool":{"must_not":[{"exists":{"field":"deleted_at"}}]}},{"term":{"level":0}},` +
+		`{"terms":{"tags":["a","b"]}},{"wildcard":{"name":{"wildcard":"jo*"}}},` +
+		`{"range":{"created_at":{"gte":"2026-10-18T00:00:00Z"}}},{"range":{"score":{"lt":0}}}]`
+	require.Equal(t, expected, string(actual), "TestFilterFromStruct")
+
+	_, err = FilterFromStruct(struct {
+		Tags string `ges:"tags,terms"`
+	}{Tags: "a"})
+	require.Error(t, err, "TestFilterFromStruct terms need slice")
+	_, err = FilterFromStruct(struct {
+		Tags string `ges:"tags,like"`
+	}{Tags: "a"})
+	require.Error(t, err, "TestFilterFromStruct op not support")
+	// tag checked even if value is zero
+	_, err = FilterFromStruct(struct {
+		Tags []string `ges:"tags,terms_"`
+	}{})
+	require.Error(t, err, "TestFilterFromStruct zero value op not support")
+	_, err = FilterFromStruct(struct {
+		Deleted *string `ges:"deleted,exists"`
+	}{})
+	require.Error(t, err, "TestFilterFromStruct nil pointer exists need bool")
+	_, err = FilterFromStruct(struct {
+		Status string `ges:"status,term,keep_zero"`
+	}{})
+	require.Error(t, err, "TestFilterFromStruct zero value option not support")
+	_, err = FilterFromStruct("status")
+	require.Error(t, err, "TestFilterFromStruct not struct")
+}
@@ -0,0 +1,156 @@
+package ges
+
+import (
+	"encoding/json"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		term level query. prefix, regexp, fuzzy, ids, terms_set
+
+***************************/
+
+// TermOptions options of term level query, zero value not send.
+// option not supported by the query is ignored. eg: prefix ignore Fuzziness
+type TermOptions struct {
+	CaseInsensitive bool
+	Boost           float64
+	// Rewrite method used to rewrite multi term query. eg: constant_score, top_terms_N
+	Rewrite string
+	// Flags regexp enabled operators. eg: ALL, INTERSECTION|COMPLEMENT
+	Flags                 string
+	MaxDeterminizedStates int
+	// Fuzziness AUTO, 0, 1, 2
+	Fuzziness     string
+	PrefixLength  int
+	MaxExpansions int
+	// Transpositions fuzzy, nil use elasticsearch default true
+	Transpositions *bool
+}
+
+func (o TermOptions) params(keys ...string) map[string]interface{} {
+	all := map[string]interface{}{
+		"case_insensitive":        o.CaseInsensitive,
+		"boost":                   o.Boost,
+		"rewrite":                 o.Rewrite,
+		"flags":                   o.Flags,
+		"max_determinized_states": o.MaxDeterminizedStates,
+		"fuzziness":               o.Fuzziness,
+		"prefix_length":           o.PrefixLength,
+		"max_expansions":          o.MaxExpansions,
+	}
+	result := pickParams(all, keys...)
+	if o.Transpositions != nil {
+		for _, key := range keys {
+			if key == "transpositions" {
+				result[key] = *o.Transpositions
+			}
+		}
+	}
+	return result
+}
+
+func firstTermOptions(opts []TermOptions) TermOptions {
+	if len(opts) == 0 {
+		return TermOptions{}
+	}
+	return opts[0]
+}
+
+// TermsSetOptions number of terms must match, one of field or script required
+type TermsSetOptions struct {
+	// MinimumShouldMatchField numeric field of document contains number of terms must match
+	MinimumShouldMatchField string `json:"minimum_should_match_field,omitempty"`
+	// MinimumShouldMatchScript script return number of terms must match. eg: Math.min(params.num_terms, doc['required'].value)
+	MinimumShouldMatchScript *Script `json:"minimum_should_match_script,omitempty"`
+	Boost                    float64 `json:"boost,omitempty"`
+}
+
+type termsSet struct {
+	name string
+	termsSetValue
+}
+
+type termsSetValue struct {
+	Terms interface{} `json:"terms"`
+	TermsSetOptions
+}
+
+func (t termsSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]map[string]interface{}{
+		"terms_set": {
+			t.name: t.termsSetValue,
+		},
+	})
+}
+
+type ids struct {
+	values []string
+}
+
+func (i ids) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]map[string]interface{}{
+		"ids": {
+			"values": i.values,
+		},
+	})
+}
+
+// Prefix field contains terms with prefix
+func (f filter) Prefix(field string, value string, opts ...TermOptions) Filter {
+	params := firstTermOptions(opts).params("case_insensitive", "boost", "rewrite")
+	params["value"] = value
+	f.condition = append(f.condition, paramQuery{kind: "prefix", field: field, params: params})
+	return f
+}
+
+// Regexp field contains terms match regular expression, Flags of options enable optional operators
+func (f filter) Regexp(field string, value string, opts ...TermOptions) Filter {
+	params := firstTermOptions(opts).params("flags", "case_insensitive", "max_determinized_states", "rewrite", "boost")
+	params["value"] = value
+	f.condition = append(f.condition, paramQuery{kind: "regexp", field: field, params: params})
+	return f
+}
+
+// Fuzzy field contains terms similar to value, measured by Levenshtein edit distance
+func (f filter) Fuzzy(field string, value string, opts ...TermOptions) Filter {
+	params := firstTermOptions(opts).params("fuzziness", "max_expansions", "prefix_length", "transpositions", "rewrite", "boost")
+	params["value"] = value
+	f.condition = append(f.condition, paramQuery{kind: "fuzzy", field: field, params: params})
+	return f
+}
+
+// TermsLookupValue values of terms fetched from field path of document
+type TermsLookupValue struct {
+	Index   string `json:"index"`
+	Id      string `json:"id"`
+	Path    string `json:"path"`
+	Routing string `json:"routing,omitempty"`
+}
+
+// TermsLookup field contains any of values in path field of document id in index
+func (f filter) TermsLookup(field, index, id, path string) Filter {
+	return f.TermsLookupWith(field, TermsLookupValue{Index: index, Id: id, Path: path})
+}
+
+// TermsLookupWith terms lookup with routing of lookup document
+func (f filter) TermsLookupWith(field string, lookup TermsLookupValue) Filter {
+	f.condition = append(f.condition, terms{field, lookup})
+	return f
+}
+
+// Ids document _id in values
+func (f filter) Ids(values ...string) Filter {
+	f.condition = append(f.condition, ids{values: values})
+	return f
+}
+
+// TermsSet field contains at least minimum number of terms, terms must be slice or array
+func (f filter) TermsSet(field string, terms interface{}, opts TermsSetOptions) Filter {
+	t := termsSet{name: field}
+	t.Terms, t.TermsSetOptions = terms, opts
+	f.condition = append(f.condition, t)
+	return f
+}
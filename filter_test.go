@@ -1,9 +1,16 @@
 package ges
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 /***************************
@@ -71,3 +78,441 @@ func TestAggDistinctAgg(t *testing.T) {
 	require.Equal(t, expected, string(actual), "TestAggDistinctAgg ")
 
 }
+
+func TestClientFilter(t *testing.T) {
+	client := ES().Where(Match("title", "elastic")).Filter(Term("status", 1), nil)
+	actual, err := json.Marshal(client)
+	require.NoError(t, err, "TestClientFilter json.Marshal")
+	expected := `{"query":{"bool":{"must":[{"match":{"title":"elastic"}}],"filter":[{"term":{"status":1}}]}}}`
+	require.Equal(t, expected, string(actual), "TestClientFilter ")
+
+	nested := NestedQuery("items", nil, nil, nil, nil).Filter(Term("items.sku", "a"))
+	actual, err = json.Marshal(BoolFilter(Term("status", 1)).Nested(nested).Result())
+	require.NoError(t, err, "TestClientFilter nested json.Marshal")
+	expected = `[{"bool":{"filter":[{"term":{"status":1}}]}},{"nested":{"path":"items","query":{"bool":{"filter":[{"term":{"items.sku":"a"}}]}}}}]`
+	require.Equal(t, expected, string(actual), "TestClientFilter nested")
+}
+
+func TestClientOr(t *testing.T) {
+	client := ES().Or(Term("a", 1), Term("b", 2))
+	actual, err := json.Marshal(client)
+	require.NoError(t, err, "TestClientOr json.Marshal")
+	expected := `{"query":{"bool":{"should":[{"term":{"a":1}},{"term":{"b":2}}]}}}`
+	require.Equal(t, expected, string(actual), "TestClientOr only or")
+
+	actual, err = json.Marshal(client.Where(Term("c", 3)))
+	require.NoError(t, err, "TestClientOr json.Marshal")
+	expected = `{"query":{"bool":{"must":[{"term":{"c":3}},{"bool":{"should":[{"term":{"a":1}},{"term":{"b":2}}],"minimum_should_match":"1"}}]}}}`
+	require.Equal(t, expected, string(actual), "TestClientOr with where")
+
+	actual, err = json.Marshal(client.Where(Term("c", 3)).MinimumShouldMatch("2"))
+	require.NoError(t, err, "TestClientOr json.Marshal")
+	expected = `{"query":{"bool":{"must":[{"term":{"c":3}}],"should":[{"term":{"a":1}},{"term":{"b":2}}],"minimum_should_match":"2"}}}`
+	require.Equal(t, expected, string(actual), "TestClientOr minimum should match")
+
+	actual, err = json.Marshal(MinimumShouldMatch("2", Term("a", 1), Term("b", 2), Term("c", 3)).Result())
+	require.NoError(t, err, "TestClientOr json.Marshal")
+	expected = `[{"bool":{"should":[{"term":{"a":1}},{"term":{"b":2}},{"term":{"c":3}}],"minimum_should_match":"2"}}]`
+	require.Equal(t, expected, string(actual), "TestClientOr filter minimum should match")
+
+	actual, err = json.Marshal(BoolWith(Term("d", 4), nil, Append(Term("a", 1), Term("b", 2), Term("c", 3)), nil,
+		BoolOptions{MinimumShouldMatch: "2"}).Result())
+	require.NoError(t, err, "TestClientOr json.Marshal")
+	expected = `[{"bool":{"must":[{"term":{"d":4}}],"should":[{"term":{"a":1}},{"term":{"b":2}},{"term":{"c":3}}],"minimum_should_match":"2"}}]`
+	require.Equal(t, expected, string(actual), "TestClientOr bool minimum should match")
+}
+
+func TestExistsAndMissing(t *testing.T) {
+	nested := NestedQuery("items", Exists("items.sku"), nil, nil, nil)
+	actual, err := json.Marshal(Exists("title").Missing("deleted_at").Nested(nested).Result())
+	require.NoError(t, err, "TestExistsAndMissing json.Marshal")
+	expected := `[{"exists":{"field":"title"}},{"bool":{"must_not":[{"exists":{"field":"deleted_at"}}]}},{"nested":{"path":"items","query":{"bool":{"must":[{"exists":{"field":"items.sku"}}]}}}}]`
+	require.Equal(t, expected, string(actual), "TestExistsAndMissing ")
+
+	aggFilters := AggFilters("filter", nil, AggFilterName("has_email").Exists("email"), AggFilterName("no_email").Missing("email"))
+	_, resultAgg := aggFilters.Result()
+	actual, err = json.Marshal(resultAgg)
+	require.NoError(t, err, "TestExistsAndMissing agg json.Marshal")
+	expected = `{"filters":{"filters":{"has_email":{"exists":{"field":"email"}},"no_email":{"bool":{"must_not":[{"exists":{"field":"email"}}]}}}}}`
+	require.Equal(t, expected, string(actual), "TestExistsAndMissing agg")
+}
+
+func TestFullTextQuery(t *testing.T) {
+	client := ES().Where(
+		MultiMatch("quick fox", []string{FieldBoost("title", 3), "body"}, MatchOptions{Type: MultiMatchBestFields, TieBreaker: 0.3}),
+		MatchPhrase("title", "quick fox", MatchOptions{Slop: 1, Operator: "and"}),
+		MatchWith("body", "quick fox", MatchOptions{Operator: "and", Fuzziness: "AUTO"}),
+		SimpleQueryString("quick + fox", MatchOptions{Operator: "and", Fields: []string{"title"}}),
+	)
+	actual, err := json.Marshal(client)
+	require.NoError(t, err, "TestFullTextQuery json.Marshal")
+	expected := `{"query":{"bool":{"must":[` +
+		`{"multi_match":{"fields":["title^3","body"],"query":"quick fox","tie_breaker":0.3,"type":"best_fields"}},` +
+		`{"match_phrase":{"title":{"query":"quick fox","slop":1}}},` +
+		`{"match":{"body":{"fuzziness":"AUTO","operator":"and","query":"quick fox"}}},` +
+		`{"simple_query_string":{"default_operator":"and","fields":["title"],"query":"quick + fox"}}]}}}`
+	require.Equal(t, expected, string(actual), "TestFullTextQuery ")
+}
+
+func TestTermLevelQuery(t *testing.T) {
+	client := ES().Where(
+		Wildcard("name", "jo*"),
+		Prefix("name", "jo", TermOptions{CaseInsensitive: true}),
+		Regexp("name", "jo.*n", TermOptions{Flags: "ALL", Boost: 1.5}),
+		Fuzzy("name", "jhon", TermOptions{Fuzziness: "AUTO"}),
+		Ids("1", "2"),
+		TermsSet("tags", []string{"go", "es"}, TermsSetOptions{MinimumShouldMatchScript: &Script{Source: "params.num_terms"}}),
+	)
+	actual, err := json.Marshal(client)
+	require.NoError(t, err, "TestTermLevelQuery json.Marshal")
+	expected := `{"query":{"bool":{"must":[` +
+		`{"wildcard":{"name":{"wildcard":"jo*"}}},` +
+		`{"prefix":{"name":{"case_insensitive":true,"value":"jo"}}},` +
+		`{"regexp":{"name":{"boost":1.5,"flags":"ALL","value":"jo.*n"}}},` +
+		`{"fuzzy":{"name":{"fuzziness":"AUTO","value":"jhon"}}},` +
+		`{"ids":{"values":["1","2"]}},` +
+		`{"terms_set":{"tags":{"terms":["go","es"],"minimum_should_match_script":{"source":"params.num_terms"}}}}]}}}`
+	require.Equal(t, expected, string(actual), "TestTermLevelQuery ")
+}
+
+func TestRangeFilter(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	client := ES().Filter(
+		Gte("created_at", start),
+		RangeWith("updated_at", NewRangeFilter().Gte("now-7d/d").Lt("now/d").TimeZone("+08:00")),
+		RangeWith("day", NewRangeFilter().Range("2024-05-01", "2024-05-31").Format("yyyy-MM-dd")),
+		RangeWith("period", NewRangeFilter().Range(1, 10).Relation(RangeWithin)),
+	)
+	actual, err := json.Marshal(client)
+	require.NoError(t, err, "TestRangeFilter json.Marshal")
+	expected := `{"query":{"bool":{"filter":[` +
+		`{"range":{"created_at":{"gte":"2024-05-01T00:00:00Z"}}},` +
+		`{"range":{"updated_at":{"lt":"now/d","gte":"now-7d/d","time_zone":"+08:00"}}},` +
+		`{"range":{"day":{"lte":"2024-05-31","gte":"2024-05-01","format":"yyyy-MM-dd"}}},` +
+		`{"range":{"period":{"lte":10,"gte":1,"relation":"WITHIN"}}}]}}}`
+	require.Equal(t, expected, string(actual), "TestRangeFilter ")
+
+	_, aggCond := AggFilterName("week").Range("day", NewRangeFilter().Gte("now-1w/d").TimeZone("+08:00")).Result()
+	actual, err = json.Marshal(aggCond)
+	require.NoError(t, err, "TestRangeFilter agg json.Marshal")
+	expected = `{"range":{"day":{"gte":"now-1w/d","time_zone":"+08:00"}}}`
+	require.Equal(t, expected, string(actual), "TestRangeFilter agg")
+}
+
+func TestGeoQuery(t *testing.T) {
+	client := ES().Filter(
+		GeoDistance("location", 40.7, -74.0, "10km"),
+		GeoBoundingBox("location", NewGeoPoint(41, -75), NewGeoPoint(40, -73)),
+		GeoShape("area", GeoEnvelope(NewGeoPoint(41, -75), NewGeoPoint(40, -73)), GeoShapeWithin),
+	).OrderByGeoDistance("location", NewGeoPoint(40.7, -74.0), false, "km").OrderBy("_score", true)
+	actual, err := json.Marshal(client)
+	require.NoError(t, err, "TestGeoQuery json.Marshal")
+	expected := `{"query":{"bool":{"filter":[` +
+		`{"geo_distance":{"distance":"10km","location":{"lat":40.7,"lon":-74}}},` +
+		`{"geo_bounding_box":{"location":{"bottom_right":{"lat":40,"lon":-73},"top_left":{"lat":41,"lon":-75}}}},` +
+		`{"geo_shape":{"area":{"relation":"WITHIN","shape":{"type":"envelope","coordinates":[[-75,41],[-73,40]]}}}}]}},` +
+		`"sort":[{"_geo_distance":{"location":{"lat":40.7,"lon":-74},"order":"asc","unit":"km"}},{"_score":{"order":"desc"}}]}`
+	require.Equal(t, expected, string(actual), "TestGeoQuery ")
+
+	_, err = json.Marshal(GeoDistance("distance", 40.7, -74.0, "10km").Result())
+	require.Error(t, err, "TestGeoQuery field named distance")
+}
+
+func TestGeoPointUnmarshal(t *testing.T) {
+	expected := NewGeoPoint(40.5, -74.25)
+	for _, raw := range []string{
+		`{"lat":40.5,"lon":-74.25}`,
+		`[-74.25,40.5]`,
+		`"40.5,-74.25"`,
+		`"POINT (-74.25 40.5)"`,
+		`{"type":"Point","coordinates":[-74.25,40.5]}`,
+	} {
+		point := GeoPoint{}
+		require.NoError(t, json.Unmarshal([]byte(raw), &point), "TestGeoPointUnmarshal json.Unmarshal "+raw)
+		require.Equal(t, expected, point, "TestGeoPointUnmarshal "+raw)
+	}
+
+	point := GeoPoint{}
+	require.NoError(t, json.Unmarshal([]byte(`"u4pruydqqvj"`), &point), "TestGeoPointUnmarshal geohash")
+	require.InDelta(t, 57.64911, point.Lat, 0.0001, "TestGeoPointUnmarshal geohash lat")
+	require.InDelta(t, 10.40744, point.Lon, 0.0001, "TestGeoPointUnmarshal geohash lon")
+
+	require.Error(t, json.Unmarshal([]byte(`"u4pr!"`), &point), "TestGeoPointUnmarshal invalid geohash")
+}
+
+func TestFunctionScore(t *testing.T) {
+	client := ES().Where(FunctionScoreWith(Match("title", "es"),
+		FunctionScoreOptions{ScoreMode: "sum", BoostMode: "multiply"},
+		GaussDecay("published_at", "now", "10d", DecayOptions{Offset: "1d"}),
+		FieldValueFactor("likes", FieldValueFactorOptions{Factor: 1.2, Modifier: FieldValueLog1p}),
+		WeightFunction(2).Filter(Term("featured", true)),
+		RandomScore(10, "_seq_no"),
+	))
+	actual, err := json.Marshal(client)
+	require.NoError(t, err, "TestFunctionScore json.Marshal")
+	expected := `{"query":{"bool":{"must":[{"function_score":{"boost_mode":"multiply","functions":[` +
+		`{"gauss":{"published_at":{"offset":"1d","origin":"now","scale":"10d"}}},` +
+		`{"field_value_factor":{"factor":1.2,"field":"likes","modifier":"log1p"}},` +
+		`{"filter":{"term":{"featured":true}},"weight":2},` +
+		`{"random_score":{"field":"_seq_no","seed":10}}],` +
+		`"query":{"match":{"title":"es"}},"score_mode":"sum"}}]}}}`
+	require.Equal(t, expected, string(actual), "TestFunctionScore ")
+
+	actual, err = json.Marshal(FunctionScore(Match("title", "es"), WeightFunction(2)).Result())
+	require.NoError(t, err, "TestFunctionScore without options json.Marshal")
+	expected = `[{"function_score":{"functions":[{"weight":2}],"query":{"match":{"title":"es"}}}}]`
+	require.Equal(t, expected, string(actual), "TestFunctionScore without options")
+
+	actual, err = json.Marshal(ScriptScore(nil, NewScript("_score * doc['likes'].value", nil)).Result())
+	require.NoError(t, err, "TestFunctionScore script score json.Marshal")
+	expected = `[{"script_score":{"query":{"match_all":{}},"script":{"source":"_score * doc['likes'].value"}}}]`
+	require.Equal(t, expected, string(actual), "TestFunctionScore script score")
+}
+
+func TestCompoundQuery(t *testing.T) {
+	client := ES().Where(
+		ConstantScore(Term("status", 1).Exists("title"), 1.5),
+		Boosting(Match("title", "apple"), Term("category", "fruit"), 0.2),
+		DisMax(0.3, Match("title", "fox"), Match("body", "fox"), nil),
+	)
+	actual, err := json.Marshal(client)
+	require.NoError(t, err, "TestCompoundQuery json.Marshal")
+	expected := `{"query":{"bool":{"must":[` +
+		`{"constant_score":{"boost":1.5,"filter":{"bool":{"must":[{"term":{"status":1}},{"exists":{"field":"title"}}]}}}},` +
+		`{"boosting":{"negative":{"term":{"category":"fruit"}},"negative_boost":0.2,"positive":{"match":{"title":"apple"}}}},` +
+		`{"dis_max":{"queries":[{"match":{"title":"fox"}},{"match":{"body":"fox"}}],"tie_breaker":0.3}}]}}}`
+	require.Equal(t, expected, string(actual), "TestCompoundQuery ")
+
+	actual, err = json.Marshal(Boosting(Match("title", "apple"), nil, 0.2).Result())
+	require.NoError(t, err, "TestCompoundQuery json.Marshal")
+	require.Equal(t, `[{"match":{"title":"apple"}}]`, string(actual), "TestCompoundQuery boosting without negative")
+}
+
+func TestJoinQuery(t *testing.T) {
+	client := ES().Where(
+		HasChild("comment", Match("body", "refund"), JoinOptions{ScoreMode: "max", MinChildren: 2, InnerHits: &InnerHits{Size: 3}}),
+		HasParent("ticket", Term("status", "open"), JoinOptions{Score: true}),
+		ParentID("comment", "t1"),
+	)
+	actual, err := json.Marshal(client)
+	require.NoError(t, err, "TestJoinQuery json.Marshal")
+	expected := `{"query":{"bool":{"must":[` +
+		`{"has_child":{"inner_hits":{"size":3},"min_children":2,"query":{"match":{"body":"refund"}},"score_mode":"max","type":"comment"}},` +
+		`{"has_parent":{"parent_type":"ticket","query":{"term":{"status":"open"}},"score":true}},` +
+		`{"parent_id":{"id":"t1","type":"comment"}}]}}}`
+	require.Equal(t, expected, string(actual), "TestJoinQuery ")
+
+	client = ES().Routing("t1")
+	require.Equal(t, "t2", client.(es).docRouting(NewChildDoc("c1", "t2", nil)), "TestJoinQuery child doc routing")
+	require.Equal(t, "t1", client.(es).docRouting(NewDoc("c1", nil)), "TestJoinQuery client routing")
+	require.Equal(t, `{"update":{"_id":"c1","routing":"t2"}}`, bulkActionLine("update", bulkActionMeta{Id: "c1", Routing: "t2"}), "TestJoinQuery bulk action")
+}
+
+func TestNestedInnerHits(t *testing.T) {
+	nested := NestedQuery("items", Term("items.sku", "a"), nil, nil, nil).
+		ScoreMode("max").IgnoreUnmapped(true).InnerHits(InnerHits{Size: 2, Source: []string{"items.sku"}})
+	actual, err := json.Marshal(nested.Result())
+	require.NoError(t, err, "TestNestedInnerHits json.Marshal")
+	expected := `{"nested":{"path":"items","query":{"bool":{"must":[{"term":{"items.sku":"a"}}]}},` +
+		`"score_mode":"max","ignore_unmapped":true,"inner_hits":{"size":2,"_source":["items.sku"]}}}`
+	require.Equal(t, expected, string(actual), "TestNestedInnerHits ")
+
+	hit := SearchResultHitResult{}
+	raw := `{"_id":"1","_source":{},"inner_hits":{"items":{"hits":{"total":{"value":1},"hits":[{"_id":"1","_source":{"sku":"a","qty":2}}]}}}}`
+	require.NoError(t, json.Unmarshal([]byte(raw), &hit), "TestNestedInnerHits json.Unmarshal")
+	items := []struct {
+		Sku string `json:"sku"`
+		Qty int    `json:"qty"`
+	}{}
+	require.NoError(t, hit.InnerHits.Decode("items", &items), "TestNestedInnerHits Decode")
+	require.Len(t, items, 1, "TestNestedInnerHits Decode")
+	require.Equal(t, "a", items[0].Sku, "TestNestedInnerHits Decode")
+	require.Equal(t, 2, items[0].Qty, "TestNestedInnerHits Decode")
+
+	raw = `{"_id":"1","_source":{},"inner_hits":{"items":{"hits":{"hits":[{"_source":{"id":9007199254740993}}]}}}}`
+	require.NoError(t, json.Unmarshal([]byte(raw), &hit), "TestNestedInnerHits json.Unmarshal")
+	rows := []map[string]interface{}{}
+	require.NoError(t, hit.InnerHits.Decode("items", &rows), "TestNestedInnerHits Decode")
+	require.Equal(t, json.Number("9007199254740993"), rows[0]["id"], "TestNestedInnerHits Decode number precision")
+}
+
+func TestTermsStrategy(t *testing.T) {
+	actual, err := json.Marshal(TermsLookup("user_id", "users", "1", "followers").Result())
+	require.NoError(t, err, "TestTermsStrategy json.Marshal")
+	expected := `[{"terms":{"user_id":{"index":"users","id":"1","path":"followers"}}}]`
+	require.Equal(t, expected, string(actual), "TestTermsStrategy lookup")
+
+	actual, err = json.Marshal(TermsLookupWith("user_id", TermsLookupValue{Index: "users", Id: "1", Path: "followers", Routing: "u1"}).Result())
+	require.NoError(t, err, "TestTermsStrategy json.Marshal")
+	expected = `[{"terms":{"user_id":{"index":"users","id":"1","path":"followers","routing":"u1"}}}]`
+	require.Equal(t, expected, string(actual), "TestTermsStrategy lookup routing")
+
+	client := ES().TermsStrategy(TermsStrategy{Limit: 2}).
+		Filter(Terms("id", []int{1, 2, 3, 4, 5}), Terms("tag", []string{"a"})).
+		Not(BoolFilter(Terms("status", []int{7, 8, 9})))
+	rewritten, err := client.(es).rewriteTerms(context.Background())
+	require.NoError(t, err, "TestTermsStrategy rewriteTerms")
+	actual, err = json.Marshal(rewritten)
+	require.NoError(t, err, "TestTermsStrategy json.Marshal")
+	expected = `{"query":{"bool":{"filter":[` +
+		`{"bool":{"should":[{"terms":{"id":[1,2]}},{"terms":{"id":[3,4]}},{"terms":{"id":[5]}}],"minimum_should_match":"1"}},` +
+		`{"terms":{"tag":["a"]}}],` +
+		`"must_not":[{"bool":{"filter":[{"bool":{"should":[{"terms":{"status":[7,8]}},{"terms":{"status":[9]}}],"minimum_should_match":"1"}}]}}]}}}`
+	require.Equal(t, expected, string(actual), "TestTermsStrategy split")
+
+	actual, err = json.Marshal(client)
+	require.NoError(t, err, "TestTermsStrategy json.Marshal")
+	require.Contains(t, string(actual), `{"terms":{"id":[1,2,3,4,5]}}`, "TestTermsStrategy client not changed")
+
+	client = ES().TermsStrategy(TermsStrategy{Limit: 2}).
+		Where(Term("type", 1).Nested(NestedQuery("items", Terms("items.sku", []string{"a", "b", "c"}), nil, nil, nil))).
+		Or(Terms("tag", []string{"x", "y", "z"}), Term("top", true))
+	rewritten, err = client.(es).rewriteTerms(context.Background())
+	require.NoError(t, err, "TestTermsStrategy rewriteTerms")
+	actual, err = json.Marshal(rewritten)
+	require.NoError(t, err, "TestTermsStrategy json.Marshal")
+	expected = `{"query":{"bool":{"must":[{"term":{"type":1}},` +
+		`{"nested":{"path":"items","query":{"bool":{"must":[` +
+		`{"bool":{"should":[{"terms":{"items.sku":["a","b"]}},{"terms":{"items.sku":["c"]}}],"minimum_should_match":"1"}}]}}}},` +
+		`{"bool":{"should":[{"bool":{"should":[{"terms":{"tag":["x","y"]}},{"terms":{"tag":["z"]}}],"minimum_should_match":"1"}},` +
+		`{"term":{"top":true}}],"minimum_should_match":"1"}}]}}}`
+	require.Equal(t, expected, string(actual), "TestTermsStrategy nested and or")
+}
+
+func TestTermsStrategyLookup(t *testing.T) {
+	var requests []string
+	restore := mockESServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, strings.TrimSpace(r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+" "+string(body)))
+		if r.Method == http.MethodPut && strings.Contains(string(body), "[4,5,6]") {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"error":{"type":"version_conflict_engine_exception"}}`))
+			return
+		}
+		if r.Method == http.MethodPut {
+			_, _ = w.Write([]byte(`{"result":"created"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"hits":{"total":{"value":0,"relation":"eq"},"hits":[]}}`))
+	})
+	defer restore()
+	// id is sha1 of {"values":[1,2,3]}
+	id := "b7467a7bc9b2aef83dabad619ab32b9283cf45ba"
+	termsLookupSaved.Delete("terms_lookup/" + id)
+
+	client := ES().IndexName("order").TermsStrategy(TermsStrategy{Limit: 2, LookupIndex: "terms_lookup"}).
+		Filter(Terms("user_id", []int{1, 2, 3}), Terms("tag", []string{"a"}))
+	rows := make([]mapStrAny, 0)
+	_, err := client.Search(context.Background(), &rows)
+	require.NoError(t, err, "TestTermsStrategyLookup Search")
+	require.Len(t, requests, 2, "TestTermsStrategyLookup requests")
+	require.Equal(t, `PUT /terms_lookup/_doc/`+id+`?op_type=create {"values":[1,2,3]}`, requests[0], "TestTermsStrategyLookup save document")
+	require.Contains(t, requests[1], `{"terms":{"user_id":{"index":"terms_lookup","id":"`+id+`","path":"values"}}},{"terms":{"tag":["a"]}}`,
+		"TestTermsStrategyLookup search body")
+	require.NotContains(t, requests[1], "refresh", "TestTermsStrategyLookup no refresh")
+
+	// document saved once
+	_, err = client.Search(context.Background(), &rows)
+	require.NoError(t, err, "TestTermsStrategyLookup Search")
+	require.Len(t, requests, 3, "TestTermsStrategyLookup document saved once")
+
+	// document created by other process
+	_, err = client.Filter(Terms("user_id", []int{4, 5, 6})).Search(context.Background(), &rows)
+	require.NoError(t, err, "TestTermsStrategyLookup conflict ignored")
+}
+
+func TestRuntimeField(t *testing.T) {
+	client := ES().RuntimeField("discount_price", MappingTypeDouble, NewScript("emit(doc['price'].value * 0.9)", nil)).
+		Where(Gt("discount_price", 10), ScriptQuery(NewScript("doc['stock'].value > params.min", map[string]interface{}{"min": 1}))).
+		OrderBy("discount_price", true).Fields("name", "discount_price")
+	actual, err := json.Marshal(client)
+	require.NoError(t, err, "TestRuntimeField json.Marshal")
+	expected := `{"query":{"bool":{"must":[{"range":{"discount_price":{"gt":10}}},` +
+		`{"script":{"script":{"source":"doc['stock'].value \u003e params.min","params":{"min":1}}}}]}},` +
+		`"sort":[{"discount_price":{"order":"desc"}}],` +
+		`"runtime_mappings":{"discount_price":{"type":"double","script":{"source":"emit(doc['price'].value * 0.9)"}}},` +
+		`"fields":["discount_price"]}`
+	require.Equal(t, expected, string(actual), "TestRuntimeField ")
+
+	type product struct {
+		Name          string   `json:"name"`
+		DiscountPrice float64  `json:"discount_price"`
+		Tags          []string `json:"tags"`
+	}
+	fields := map[string]json.RawMessage{"discount_price": json.RawMessage(`[9.9]`), "tags": json.RawMessage(`["a"]`)}
+	source, err := mergeHitFields(json.RawMessage(`{"name":"a"}`), fields, reflect.TypeOf(&product{}))
+	require.NoError(t, err, "TestRuntimeField mergeHitFields")
+	require.Equal(t, `{"discount_price":9.9,"name":"a","tags":["a"]}`, string(source), "TestRuntimeField mergeHitFields struct")
+
+	source, err = mergeHitFields(json.RawMessage(`{"name":"a"}`), fields, reflect.TypeOf(map[string]interface{}{}))
+	require.NoError(t, err, "TestRuntimeField mergeHitFields")
+	require.Equal(t, `{"discount_price":[9.9],"name":"a","tags":["a"]}`, string(source), "TestRuntimeField mergeHitFields map")
+
+	_, err = mergeHitFields(nil, map[string]json.RawMessage{"discount_price": json.RawMessage(`[9.9,8.8]`)}, reflect.TypeOf(product{}))
+	require.Error(t, err, "TestRuntimeField mergeHitFields multiple values to scalar")
+}
+
+func TestPercolate(t *testing.T) {
+	actual, err := json.Marshal(Percolate("query", map[string]interface{}{"level": "error"}).Result())
+	require.NoError(t, err, "TestPercolate json.Marshal")
+	expected := `[{"percolate":{"document":{"level":"error"},"field":"query"}}]`
+	require.Equal(t, expected, string(actual), "TestPercolate one document")
+
+	actual, err = json.Marshal(Percolate("query", map[string]interface{}{"level": "error"}, map[string]interface{}{"level": "warn"}).Result())
+	require.NoError(t, err, "TestPercolate json.Marshal")
+	expected = `[{"percolate":{"documents":[{"level":"error"},{"level":"warn"}],"field":"query"}}]`
+	require.Equal(t, expected, string(actual), "TestPercolate documents")
+}
+
+func TestPercolateRequest(t *testing.T) {
+	var requests []string
+	total := 2
+	restore := mockESServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, strings.TrimSpace(r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+" "+string(body)))
+		if r.Method == http.MethodPut {
+			_, _ = w.Write([]byte(`{"result":"created"}`))
+			return
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"hits":{"total":{"value":%d,"relation":"eq"},"hits":[{"_id":"alert-1"},{"_id":"alert-2"}]}}`, total)))
+	})
+	defer restore()
+
+	client := ES().IndexName("alert")
+	err := client.RegisterQuery(context.Background(), "query", "alert-1", Term("level", "error"))
+	require.NoError(t, err, "TestPercolateRequest RegisterQuery")
+	require.Equal(t, `PUT /alert/_doc/alert-1?refresh=true {"query":{"term":{"level":"error"}}}`, requests[0],
+		"TestPercolateRequest RegisterQuery request")
+
+	ids, err := client.Percolate(context.Background(), "query", map[string]interface{}{"level": "error"})
+	require.NoError(t, err, "TestPercolateRequest Percolate")
+	require.Equal(t, []string{"alert-1", "alert-2"}, ids, "TestPercolateRequest Percolate")
+	require.Contains(t, requests[1], "POST /alert/_search?", "TestPercolateRequest Percolate path")
+	require.Contains(t, requests[1], "_source=false", "TestPercolateRequest Percolate source disabled")
+	require.Contains(t, requests[1], "size=10000", "TestPercolateRequest Percolate size")
+	require.Contains(t, requests[1], `{"percolate":{"document":{"level":"error"},"field":"query"}}`, "TestPercolateRequest Percolate body")
+
+	// matched more than returned
+	total = 10001
+	_, err = client.Percolate(context.Background(), "query", map[string]interface{}{"level": "error"})
+	require.Error(t, err, "TestPercolateRequest Percolate truncated")
+	ids, err = client.Size(2).Percolate(context.Background(), "query", map[string]interface{}{"level": "error"})
+	require.NoError(t, err, "TestPercolateRequest Percolate with size")
+	require.Len(t, ids, 2, "TestPercolateRequest Percolate with size")
+}
+
+func TestMoreLikeThis(t *testing.T) {
+	minTermFreq, minDocFreq := 1, 0
+	client := ES().Where(MoreLikeThis([]string{"title", "body"}, []string{"elasticsearch"}, []DocRef{{Index: "article", Id: "1"}},
+		MoreLikeThisOptions{MinTermFreq: &minTermFreq, MinDocFreq: &minDocFreq, MaxQueryTerms: 12, UnlikeTexts: []string{"kibana"}})).Not(Ids("1"))
+	actual, err := json.Marshal(client)
+	require.NoError(t, err, "TestMoreLikeThis json.Marshal")
+	expected := `{"query":{"bool":{"must":[{"more_like_this":{"fields":["title","body"],` +
+		`"like":["elasticsearch",{"_index":"article","_id":"1"}],"max_query_terms":12,"min_doc_freq":0,"min_term_freq":1,"unlike":["kibana"]}}],` +
+		`"must_not":[{"ids":{"values":["1"]}}]}}}`
+	require.Equal(t, expected, string(actual), "TestMoreLikeThis ")
+
+	actual, err = json.Marshal(MoreLikeThis([]string{"title"}, nil, nil).Result())
+	require.NoError(t, err, "TestMoreLikeThis json.Marshal")
+	require.Equal(t, `[{"match_none":{}}]`, string(actual), "TestMoreLikeThis empty like")
+}
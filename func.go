@@ -11,6 +11,24 @@ package ges
 */
 func Match(field string, value interface{}) Filter { return filter{}.Match(field, value) }
 func Term(field string, value interface{}) Filter  { return filter{}.Term(field, value) }
+func MatchWith(field string, value interface{}, opts ...MatchOptions) Filter {
+	return filter{}.MatchWith(field, value, opts...)
+}
+func MatchPhrase(field string, text string, opts ...MatchOptions) Filter {
+	return filter{}.MatchPhrase(field, text, opts...)
+}
+func MatchPhrasePrefix(field string, text string, opts ...MatchOptions) Filter {
+	return filter{}.MatchPhrasePrefix(field, text, opts...)
+}
+func MultiMatch(text string, fields []string, opts ...MatchOptions) Filter {
+	return filter{}.MultiMatch(text, fields, opts...)
+}
+func QueryString(query string, opts ...MatchOptions) Filter {
+	return filter{}.QueryString(query, opts...)
+}
+func SimpleQueryString(query string, opts ...MatchOptions) Filter {
+	return filter{}.SimpleQueryString(query, opts...)
+}
 func Terms(field string, values interface{}) Filter {
 	return filter{}.Terms(field, values)
 }
@@ -18,25 +36,74 @@ func TermsSingeItem(field string, value interface{}) Filter {
 	return filter{}.TermsSingeItem(field, value)
 }
 
-func Between(field string, start, end int64) Filter {
+func Between(field string, start, end interface{}) Filter {
 	return filter{}.Between(field, start, end)
 }
-func Gt(field string, value int64) Filter              { return filter{}.Gt(field, value) }
-func Gte(field string, value int64) Filter             { return filter{}.Gte(field, value) }
-func Lt(field string, value int64) Filter              { return filter{}.Lt(field, value) }
-func Lte(field string, value int64) Filter             { return filter{}.Lte(field, value) }
-func Wildcard(field string, value string) Filter       { return filter{}.Wildcard(field, value) }
-func WildcardSuffix(field string, value string) Filter { return filter{}.WildcardSuffix(field, value) }
+func Gt(field string, value interface{}) Filter  { return filter{}.Gt(field, value) }
+func Gte(field string, value interface{}) Filter { return filter{}.Gte(field, value) }
+func Lt(field string, value interface{}) Filter  { return filter{}.Lt(field, value) }
+func Lte(field string, value interface{}) Filter { return filter{}.Lte(field, value) }
+func Wildcard(field string, value string, opts ...TermOptions) Filter {
+	return filter{}.Wildcard(field, value, opts...)
+}
+func WildcardSuffix(field string, value string, opts ...TermOptions) Filter {
+	return filter{}.WildcardSuffix(field, value, opts...)
+}
+func Prefix(field string, value string, opts ...TermOptions) Filter {
+	return filter{}.Prefix(field, value, opts...)
+}
+func Regexp(field string, value string, opts ...TermOptions) Filter {
+	return filter{}.Regexp(field, value, opts...)
+}
+func Fuzzy(field string, value string, opts ...TermOptions) Filter {
+	return filter{}.Fuzzy(field, value, opts...)
+}
+func Ids(values ...string) Filter { return filter{}.Ids(values...) }
+func TermsSet(field string, terms interface{}, opts TermsSetOptions) Filter {
+	return filter{}.TermsSet(field, terms, opts)
+}
+
+// TermsLookup field contains any of values in path field of document id in index
+func TermsLookup(field, index, id, path string) Filter {
+	return filter{}.TermsLookup(field, index, id, path)
+}
+
+// TermsLookupWith terms lookup with routing of lookup document
+func TermsLookupWith(field string, lookup TermsLookupValue) Filter {
+	return filter{}.TermsLookupWith(field, lookup)
+}
+func Exists(field string) Filter  { return filter{}.Exists(field) }
+func Missing(field string) Filter { return filter{}.Missing(field) }
+func GeoDistance(field string, lat, lon float64, distance string) Filter {
+	return filter{}.GeoDistance(field, lat, lon, distance)
+}
+func GeoBoundingBox(field string, topLeft, bottomRight GeoPoint) Filter {
+	return filter{}.GeoBoundingBox(field, topLeft, bottomRight)
+}
+func GeoPolygon(field string, points ...GeoPoint) Filter {
+	return filter{}.GeoPolygon(field, points...)
+}
+func GeoShape(field string, shape GeoGeometry, relation GeoShapeRelation) Filter {
+	return filter{}.GeoShape(field, shape, relation)
+}
 func Bool(must, not, should, match Filter, adjustPureNegative bool) Filter {
 	return filter{}.BoolItem(must, not, should, match, adjustPureNegative)
 }
 
+// BoolWith bool query with options. eg: BoolWith(nil, nil, should, nil, BoolOptions{MinimumShouldMatch: "2"})
+func BoolWith(must, not, should, match Filter, opts BoolOptions) Filter {
+	return filter{}.BoolWith(must, not, should, match, opts)
+}
+
 // Range  range query. [start, end], start <= field <= end
 func Range(field string, start, end interface{}) Filter { return filter{}.Range(field, start, end) }
 
 // FromTo  range query. [from, to), from <= field < to
 func FromTo(field string, from, to interface{}) Filter { return filter{}.FromTo(field, from, to) }
 
+// RangeWith range query with options. eg: RangeWith("ts", NewRangeFilter().Gte("now-7d/d").TimeZone("+08:00"))
+func RangeWith(field string, r RangeFilter) Filter { return filter{}.RangeWith(field, r) }
+
 func NestedQuery(path string, must, not, should, match Filter) NestedFilter {
 	return esNested{}.Path(path).Must(must).Not(not).Should(should).Match(match)
 }
@@ -49,6 +116,74 @@ func Nested() NestedFilter {
 	return esNested{}
 }
 
+// BoolFilter bool query with filter context
+func BoolFilter(filters ...Filter) Filter {
+	return filter{}.BoolFilter(filters...)
+}
+
+// MinimumShouldMatch bool query, at least minimumShouldMatch of should conditions must match
+func MinimumShouldMatch(minimumShouldMatch string, should ...Filter) Filter {
+	return filter{}.MinimumShouldMatch(minimumShouldMatch, should...)
+}
+
+// FunctionScore modify score of documents match query by functions
+func FunctionScore(query Filter, functions ...ScoreFunction) Filter {
+	return filter{}.FunctionScore(query, functions...)
+}
+
+// FunctionScoreWith function_score with options
+func FunctionScoreWith(query Filter, opts FunctionScoreOptions, functions ...ScoreFunction) Filter {
+	return filter{}.FunctionScoreWith(query, opts, functions...)
+}
+
+func ScriptScore(query Filter, script Script, minScore ...float64) Filter {
+	return filter{}.ScriptScore(query, script, minScore...)
+}
+
+// ConstantScore documents match filter get same score boost
+func ConstantScore(f Filter, boost float64) Filter {
+	return filter{}.ConstantScore(f, boost)
+}
+
+// Boosting demote documents match negative by negativeBoost
+func Boosting(positive, negative Filter, negativeBoost float64) Filter {
+	return filter{}.Boosting(positive, negative, negativeBoost)
+}
+
+// DisMax score is max score of matched queries plus tieBreaker * other scores
+func DisMax(tieBreaker float64, queries ...Filter) Filter {
+	return filter{}.DisMax(tieBreaker, queries...)
+}
+
+// HasChild parent documents which child documents match query
+func HasChild(childType string, query Filter, opts ...JoinOptions) Filter {
+	return filter{}.HasChild(childType, query, opts...)
+}
+
+// HasParent child documents which parent document match query
+func HasParent(parentType string, query Filter, opts ...JoinOptions) Filter {
+	return filter{}.HasParent(parentType, query, opts...)
+}
+
+func ParentID(childType, id string) Filter {
+	return filter{}.ParentID(childType, id)
+}
+
+// ScriptQuery documents which script return true
+func ScriptQuery(script Script) Filter {
+	return filter{}.Script(script)
+}
+
+// Percolate stored queries in percolator field match any of docs
+func Percolate(field string, docs ...interface{}) Filter {
+	return filter{}.Percolate(field, docs...)
+}
+
+// MoreLikeThis documents similar to likeTexts and likeDocs
+func MoreLikeThis(fields []string, likeTexts []string, likeDocs []DocRef, opts ...MoreLikeThisOptions) Filter {
+	return filter{}.MoreLikeThis(fields, likeTexts, likeDocs, opts...)
+}
+
 func BoolTrue(must, not, should, match Filter) Filter {
 	return filter{}.BoolItem(must, not, should, match, true)
 }
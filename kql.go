@@ -0,0 +1,347 @@
+package ges
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		kibana query language(KQL) to Filter, fields limited by FieldSchema.
+		eg: status:open and priority >= 3 and not tags:(spam or test) and items:{ sku:a* and qty > 1 }
+
+***************************/
+
+const (
+	// maxKQLDepth max nesting of parentheses and nested query
+	maxKQLDepth = 32
+	// maxKQLLength max characters of query
+	maxKQLLength = 4096
+	// maxKQLNodes max number of field values, range and nested query
+	maxKQLNodes = 100
+)
+
+// kqlLang Lang of KQL syntax error
+const kqlLang = "kql"
+
+const kqlSpecial = `():{}<>="\`
+
+func kqlTokenize(query string) ([]queryToken, error) {
+	runes := []rune(query)
+	tokens := make([]queryToken, 0, len(runes)/2)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"':
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, &QuerySyntaxError{Lang: kqlLang, Query: query, Pos: pos, Msg: "unterminated quoted value"}
+				}
+				if runes[i] == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenString, text: sb.String(), pos: pos})
+		case r == '<' || r == '>':
+			symbol := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				symbol += "="
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenSymbol, text: symbol, pos: pos})
+			i += len(symbol)
+		case strings.ContainsRune("():{}", r):
+			tokens = append(tokens, queryToken{kind: queryTokenSymbol, text: string(r), pos: pos})
+			i++
+		default:
+			var sb strings.Builder
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(kqlSpecial, runes[i]) {
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i < len(runes) && runes[i] == '\\' {
+				return nil, &QuerySyntaxError{Lang: kqlLang, Query: query, Pos: i + 1, Msg: "escape only supported in quoted value"}
+			}
+			if sb.Len() == 0 {
+				return nil, &QuerySyntaxError{Lang: kqlLang, Query: query, Pos: pos, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			word := sb.String()
+			switch strings.ToLower(word) {
+			case "and", "or", "not":
+				tokens = append(tokens, queryToken{kind: queryTokenKeyword, text: strings.ToLower(word), pos: pos})
+			default:
+				tokens = append(tokens, queryToken{kind: queryTokenWord, text: word, pos: pos})
+			}
+		}
+	}
+	tokens = append(tokens, queryToken{kind: queryTokenEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
+
+type kqlParser struct {
+	queryParser
+	schema FieldSchema
+	depth  int
+	nodes  int
+	// nestedPath path of nested query being parsed, field relative to it
+	nestedPath string
+}
+
+// ParseKQL parse KQL to Filter, field not in schema and value not match field type is error.
+// support field:value, field:"phrase", field:(a or b), field:*, field:prefix*, field < value, and, or, not, (),
+// nested path:{ query }, field of nested object not in path:{} wrapped by nested query automatically.
+// query limited to maxKQLLength characters, maxKQLNodes values and maxKQLDepth levels
+func ParseKQL(query string, schema FieldSchema) (Filter, error) {
+	if len([]rune(query)) > maxKQLLength {
+		return nil, &QuerySyntaxError{Lang: kqlLang, Query: query, Pos: maxKQLLength + 1, Msg: fmt.Sprintf("query longer than %d characters", maxKQLLength)}
+	}
+	tokens, err := kqlTokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &kqlParser{queryParser: queryParser{lang: kqlLang, query: query, tokens: tokens}, schema: schema}
+	if p.peek().kind == queryTokenEOF {
+		return nil, p.errorf(p.peek(), "empty query")
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != queryTokenEOF {
+		return nil, p.errorf(tok, "unexpected %s, missing and/or", tok.describe())
+	}
+	return schemaFilter(node), nil
+}
+
+func (p *kqlParser) enter(tok queryToken) error {
+	p.depth++
+	if p.depth > maxKQLDepth {
+		return p.errorf(tok, "query nested more than %d levels", maxKQLDepth)
+	}
+	return nil
+}
+
+// node count value, range and nested query
+func (p *kqlParser) node(tok queryToken) error {
+	p.nodes++
+	if p.nodes > maxKQLNodes {
+		return p.errorf(tok, "query has more than %d values", maxKQLNodes)
+	}
+	return nil
+}
+
+func (p *kqlParser) parseOr() (interface{}, error) {
+	return p.queryParser.parseOr("or", "and", p.parseNot)
+}
+
+func (p *kqlParser) parseNot() (interface{}, error) {
+	if p.isKeyword("not") {
+		tok := p.next()
+		if err := p.enter(tok); err != nil {
+			return nil, err
+		}
+		item, err := p.parseNot()
+		p.depth--
+		if err != nil {
+			return nil, err
+		}
+		return boolFilter{Bool: esQueryBool{Not: []interface{}{item}}}, nil
+	}
+	if p.isSymbol("(") {
+		tok := p.next()
+		if err := p.enter(tok); err != nil {
+			return nil, err
+		}
+		item, err := p.parseOr()
+		p.depth--
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return item, nil
+	}
+	return p.parseField()
+}
+
+func (p *kqlParser) parseField() (interface{}, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != queryTokenWord {
+		return nil, p.errorf(fieldTok, "expect field name, got %s", fieldTok.describe())
+	}
+	field := fieldTok.text
+	if p.nestedPath != "" {
+		field = p.nestedPath + "." + field
+	}
+	fieldType, err := p.schema.fieldType(field)
+	if err != nil {
+		return nil, p.errorf(fieldTok, "%s", err.Error())
+	}
+
+	opTok := p.next()
+	if opTok.kind != queryTokenSymbol {
+		return nil, p.errorf(opTok, "expect : or range operator after field %s, got %s", fieldTok.text, opTok.describe())
+	}
+	var item interface{}
+	switch opTok.text {
+	case "<", "<=", ">", ">=":
+		if !isRangeMappingType(fieldType) {
+			return nil, p.errorf(opTok, "field %s is %s, range not supported", field, fieldType)
+		}
+		item, err = p.parseRange(field, fieldType, opTok.text)
+	case ":":
+		if p.isSymbol("{") {
+			return p.parseNested(field, fieldType)
+		}
+		item, err = p.parseValueExpr(field, fieldType)
+	default:
+		return nil, p.errorf(opTok, "expect : or range operator after field %s, got %s", fieldTok.text, opTok.describe())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.schema.wrapNested(p.nestedPath, field, item), nil
+}
+
+func (p *kqlParser) parseRange(field string, fieldType MappingType, op string) (interface{}, error) {
+	tok := p.next()
+	if tok.kind != queryTokenWord && tok.kind != queryTokenString {
+		return nil, p.errorf(tok, "expect value, got %s", tok.describe())
+	}
+	if err := p.node(tok); err != nil {
+		return nil, err
+	}
+	value, err := schemaValue(field, fieldType, tok.text)
+	if err != nil {
+		return nil, p.errorf(tok, "%s", err.Error())
+	}
+	return schemaRange(field, op, value), nil
+}
+
+// parseNested path:{ query }, field in query relative to path
+func (p *kqlParser) parseNested(path string, fieldType MappingType) (interface{}, error) {
+	tok := p.next()
+	if fieldType != MappingTypeNested {
+		return nil, p.errorf(tok, "field %s is not nested", path)
+	}
+	if err := p.node(tok); err != nil {
+		return nil, err
+	}
+	if err := p.enter(tok); err != nil {
+		return nil, err
+	}
+	parentPath := p.nestedPath
+	p.nestedPath = path
+	item, err := p.parseOr()
+	p.nestedPath = parentPath
+	p.depth--
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectSymbol("}"); err != nil {
+		return nil, err
+	}
+	nested := esNested{NestedPath: path}
+	nested.Query.Bool.Must = []interface{}{item}
+	return p.schema.wrapNested(parentPath, path, nested.Result()), nil
+}
+
+// parseValueExpr value or (value or value ...) of field
+func (p *kqlParser) parseValueExpr(field string, fieldType MappingType) (interface{}, error) {
+	if !p.isSymbol("(") {
+		tok := p.next()
+		return p.valueQuery(field, fieldType, tok)
+	}
+	tok := p.next()
+	if err := p.enter(tok); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+
+	var items []interface{}
+	op := ""
+	for {
+		valueTok := p.next()
+		item, err := p.valueQuery(field, fieldType, valueTok)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if p.isSymbol(")") {
+			p.next()
+			break
+		}
+		opTok := p.next()
+		if opTok.kind != queryTokenKeyword || opTok.text == "not" || (op != "" && op != opTok.text) {
+			return nil, p.errorf(opTok, "expect same and/or between values of field %s, got %s", field, opTok.describe())
+		}
+		op = opTok.text
+	}
+	if len(items) == 1 {
+		return items[0], nil
+	}
+	if op == "and" {
+		return boolFilter{Bool: esQueryBool{Must: items}}, nil
+	}
+	// or of term values as one terms query
+	values := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		t, ok := item.(term)
+		if !ok {
+			return boolFilter{Bool: esQueryBool{Should: items, MinimumShouldMatch: "1"}}, nil
+		}
+		values = append(values, t.value)
+	}
+	return terms{name: field, values: values}, nil
+}
+
+// valueQuery query of single value by field type. * is exists, text is match, value with * is wildcard
+func (p *kqlParser) valueQuery(field string, fieldType MappingType, tok queryToken) (interface{}, error) {
+	if err := p.node(tok); err != nil {
+		return nil, err
+	}
+	switch tok.kind {
+	case queryTokenString:
+		if fieldType == MappingTypeText {
+			return paramQuery{kind: "match_phrase", field: field, params: map[string]interface{}{"query": tok.text}}, nil
+		}
+	case queryTokenWord:
+		if tok.text == "*" {
+			return exists{name: field}, nil
+		}
+		if strings.ContainsAny(tok.text, "*?") {
+			if fieldType != MappingTypeKeyword && fieldType != MappingTypeText {
+				return nil, p.errorf(tok, "field %s is %s, wildcard not supported", field, fieldType)
+			}
+			w := wildCard{name: field}
+			w.Wildcard = tok.text
+			return w, nil
+		}
+		if fieldType == MappingTypeText {
+			return match{name: field, value: tok.text}, nil
+		}
+	default:
+		return nil, p.errorf(tok, "expect value of field %s, got %s", field, tok.describe())
+	}
+	value, err := schemaValue(field, fieldType, tok.text)
+	if err != nil {
+		return nil, p.errorf(tok, "%s", err.Error())
+	}
+	return term{name: field, value: value}, nil
+}
@@ -0,0 +1,95 @@
+package ges
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+
+***************************/
+
+var testKQLSchema = FieldSchema{
+	"status":    MappingTypeKeyword,
+	"priority":  MappingTypeInteger,
+	"tags":      MappingTypeKeyword,
+	"title":     MappingTypeText,
+	"items":     MappingTypeNested,
+	"items.sku": MappingTypeKeyword,
+	"items.qty": MappingTypeLong,
+	"views":     MappingTypeUnsignedLong,
+}
+
+func TestParseKQL(t *testing.T) {
+	f, err := ParseKQL(`status:open and priority >= 3 and not tags:(spam or test) and title:"quick fox"`, testKQLSchema)
+	require.NoError(t, err, "TestParseKQL ParseKQL")
+	actual, err := json.Marshal(f.Result())
+	require.NoError(t, err, "TestParseKQL json.Marshal")
+	expected := `[{"term":{"status":"open"}},{"range":{"priority":{"gte":3}}},` +
+		`{"bool":{"must_not":[{"terms":{"tags":["spam","test"]}}]}},` +
+		`{"match_phrase":{"title":{"query":"quick fox"}}}]`
+	require.Equal(t, expected, string(actual), "TestParseKQL")
+
+	f, err = ParseKQL(`items:{ sku:a* and qty > 1 } or items.sku:b or status:*`, testKQLSchema)
+	require.NoError(t, err, "TestParseKQL ParseKQL nested")
+	actual, err = json.Marshal(f.Result())
+	require.NoError(t, err, "TestParseKQL json.Marshal")
+	expected = `[{"bool":{"should":[` +
+		`{"nested":{"path":"items","query":{"bool":{"must":[{"bool":{"must":[{"wildcard":{"items.sku":{"wildcard":"a*"}}},{"range":{"items.qty":{"gt":1}}}]}}]}}}},` +
+		`{"nested":{"path":"items","query":{"bool":{"must":[{"term":{"items.sku":"b"}}]}}}},` +
+		`{"exists":{"field":"status"}}],"minimum_should_match":"1"}}]`
+	require.Equal(t, expected, string(actual), "TestParseKQL nested")
+
+	// unsigned_long larger than max int64
+	f, err = ParseKQL(`views >= 18446744073709551615`, testKQLSchema)
+	require.NoError(t, err, "TestParseKQL ParseKQL unsigned_long")
+	actual, err = json.Marshal(f.Result())
+	require.NoError(t, err, "TestParseKQL json.Marshal")
+	require.Equal(t, `[{"range":{"views":{"gte":18446744073709551615}}}]`, string(actual), "TestParseKQL unsigned_long")
+	_, err = ParseKQL(`views:-1`, testKQLSchema)
+	require.Error(t, err, "TestParseKQL unsigned_long negative")
+}
+
+func TestParseKQLError(t *testing.T) {
+	for query, pos := range map[string]int{
+		"password:1":             1,
+		"priority:high":          10,
+		"status >= a":            8,
+		"status:open priority:3": 13,
+		"(status:open":           13,
+		"status:(a or b and c)":  16,
+		"status:{ a:1 }":         8,
+		"items:{ password:1 }":   9,
+		`title:"quick`:           7,
+	} {
+		_, err := ParseKQL(query, testKQLSchema)
+		syntaxErr := &QuerySyntaxError{}
+		require.True(t, errors.As(err, &syntaxErr), "TestParseKQLError "+query)
+		require.Equal(t, pos, syntaxErr.Pos, "TestParseKQLError position "+query)
+		require.Equal(t, kqlLang, syntaxErr.Lang, "TestParseKQLError lang "+query)
+	}
+
+	// limits of query length and values
+	for query, pos := range map[string]int{
+		"status:" + strings.Repeat("a", maxKQLLength):            maxKQLLength + 1,
+		"status:(" + strings.Repeat("a or ", maxKQLNodes) + "a)": 8 + 5*maxKQLNodes + 1,
+	} {
+		_, err := ParseKQL(query, testKQLSchema)
+		syntaxErr := &QuerySyntaxError{}
+		require.True(t, errors.As(err, &syntaxErr), "TestParseKQLError limit")
+		require.Equal(t, pos, syntaxErr.Pos, "TestParseKQLError limit position")
+	}
+
+	schema := FieldSchemaFromMapping(map[string]MappingField{
+		"title": {Type: MappingTypeText, Fields: map[string]MappingField{"keyword": {Type: MappingTypeKeyword}}},
+		"items": {Type: MappingTypeNested, Properties: map[string]MappingField{"sku": {Type: MappingTypeKeyword}}},
+	})
+	require.Equal(t, FieldSchema{"title": MappingTypeText, "title.keyword": MappingTypeKeyword,
+		"items": MappingTypeNested, "items.sku": MappingTypeKeyword}, schema, "TestParseKQLError FieldSchemaFromMapping")
+}
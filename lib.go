@@ -10,6 +10,8 @@ package ges
 type doc struct {
 	Id       string
 	Document interface{}
+	// routing shard routing of document, child document use parent id
+	routing string
 }
 
 func (d doc) ID() string {
@@ -23,10 +25,19 @@ func (d doc) Item() (string, interface{}) {
 	return d.Id, d.Document
 }
 
+func (d doc) Routing() string {
+	return d.routing
+}
+
 func NewDoc(id string, d interface{}) Document {
 	return doc{Id: id, Document: d}
 }
 
+// NewChildDoc child document of join field, routing to shard of parent
+func NewChildDoc(id, parentID string, d interface{}) Document {
+	return doc{Id: id, Document: d, routing: parentID}
+}
+
 func DocsFromMap(docs map[string]interface{}) []Document {
 	res := make([]Document, 0, len(docs))
 	for id, d := range docs {
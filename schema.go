@@ -0,0 +1,271 @@
+package ges
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		field whitelist and type of user input query, shared by sql where, KQL and filter spec.
+		syntax error, token and and/or precedence of sql where and KQL parser
+
+***************************/
+
+// FieldSchema allowed fields and type, field not in schema is rejected.
+// nested field use full path and nested path has MappingTypeNested. eg: {"items": nested, "items.sku": keyword}
+type FieldSchema map[string]MappingType
+
+// FieldSchemaFromMapping all fields of index mapping, multi-fields included. eg: title.keyword
+func FieldSchemaFromMapping(properties map[string]MappingField) FieldSchema {
+	schema := make(FieldSchema)
+	schema.addMapping("", properties)
+	return schema
+}
+
+func (s FieldSchema) addMapping(prefix string, properties map[string]MappingField) {
+	for name, field := range properties {
+		fullName := prefix + name
+		if field.Type != "" {
+			s[fullName] = field.Type
+		}
+		for subName, sub := range field.Fields {
+			s[fullName+"."+subName] = sub.Type
+		}
+		if len(field.Properties) != 0 {
+			s.addMapping(fullName+".", field.Properties)
+		}
+	}
+}
+
+// fieldType type of allowed field, error if field not in schema
+func (s FieldSchema) fieldType(field string) (MappingType, error) {
+	fieldType, ok := s[field]
+	if !ok {
+		return "", fmt.Errorf("field %s not allowed", field)
+	}
+	return fieldType, nil
+}
+
+// nestedPath nearest nested path of field, empty if field not in nested object
+func (s FieldSchema) nestedPath(field string) string {
+	for idx := strings.LastIndex(field, "."); idx > 0; idx = strings.LastIndex(field[:idx], ".") {
+		if s[field[:idx]] == MappingTypeNested {
+			return field[:idx]
+		}
+	}
+	return ""
+}
+
+// wrapNested item of field wrapped by nested query of its path, skip path already wrapped by parent
+func (s FieldSchema) wrapNested(parentPath, field string, item interface{}) interface{} {
+	path := s.nestedPath(field)
+	if path == "" || path == parentPath {
+		return item
+	}
+	nested := esNested{NestedPath: path}
+	nested.Query.Bool.Must = []interface{}{s.wrapNested(parentPath, path, item)}
+	return nested.Result()
+}
+
+// schemaFilter filter of parsed query, top level and conditions as separate conditions of filter
+func schemaFilter(item interface{}) Filter {
+	if b, ok := item.(boolFilter); ok && len(b.Bool.Must) != 0 && len(b.Bool.Should)+len(b.Bool.Not) == 0 {
+		return filter{condition: b.Bool.Must}
+	}
+	return filter{condition: []interface{}{item}}
+}
+
+// schemaRange range query of field, op is <, <=, >, >= or gt, gte, lt, lte
+func schemaRange(field, op string, value interface{}) between {
+	b := between{name: field}
+	switch op {
+	case "<", string(FilterOpLt):
+		b.LtPtr = rangeValue(value)
+	case "<=", string(FilterOpLte):
+		b.LtePtr = rangeValue(value)
+	case ">", string(FilterOpGt):
+		b.GtPtr = rangeValue(value)
+	case ">=", string(FilterOpGte):
+		b.GtePtr = rangeValue(value)
+	}
+	return b
+}
+
+func isNumericMappingType(fieldType MappingType) bool {
+	switch fieldType {
+	case MappingTypeLong, MappingTypeInteger, MappingTypeShort, MappingTypeByte, MappingTypeDouble, MappingTypeFloat,
+		MappingTypeHalfFloat, MappingTypeScaledFloat, MappingTypeUnsignedLong:
+		return true
+	}
+	return false
+}
+
+func isRangeMappingType(fieldType MappingType) bool {
+	return isNumericMappingType(fieldType) || fieldType == MappingTypeDate
+}
+
+// schemaValue text value converted to type of field, error if value not match type
+func schemaValue(field string, fieldType MappingType, text string) (interface{}, error) {
+	switch {
+	case fieldType == MappingTypeBoolean:
+		val, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("field %s is boolean, value %s invalid", field, text)
+		}
+		return val, nil
+	case fieldType == MappingTypeUnsignedLong:
+		val, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("field %s is %s, value %s invalid", field, fieldType, text)
+		}
+		return val, nil
+	case fieldType == MappingTypeLong || fieldType == MappingTypeInteger || fieldType == MappingTypeShort ||
+		fieldType == MappingTypeByte:
+		val, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("field %s is %s, value %s invalid", field, fieldType, text)
+		}
+		return val, nil
+	case isNumericMappingType(fieldType):
+		val, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("field %s is %s, value %s invalid", field, fieldType, text)
+		}
+		return val, nil
+	case fieldType == MappingTypeNested:
+		return nil, fmt.Errorf("field %s is nested, query inner field", field)
+	default:
+		return text, nil
+	}
+}
+
+// QuerySyntaxError syntax error of sql where clause and KQL query, Pos is 1-based character position in query
+type QuerySyntaxError struct {
+	// Lang sql where or kql
+	Lang  string
+	Query string
+	Pos   int
+	Msg   string
+}
+
+func (e *QuerySyntaxError) Error() string {
+	return fmt.Sprintf("%s syntax error at position %d: %s", e.Lang, e.Pos, e.Msg)
+}
+
+type queryTokenKind int
+
+const (
+	queryTokenEOF queryTokenKind = iota
+	// queryTokenWord field name of sql, field name or unquoted value of KQL
+	queryTokenWord
+	queryTokenKeyword
+	// queryTokenString quoted string, quotes removed
+	queryTokenString
+	queryTokenNumber
+	queryTokenParam
+	queryTokenSymbol
+)
+
+type queryToken struct {
+	kind queryTokenKind
+	text string
+	pos  int
+}
+
+func (t queryToken) describe() string {
+	switch t.kind {
+	case queryTokenEOF:
+		return "end of query"
+	case queryTokenString:
+		return fmt.Sprintf("string %q", t.text)
+	case queryTokenParam:
+		return "placeholder ?"
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// queryParser token cursor of sql where and KQL parser
+type queryParser struct {
+	lang   string
+	query  string
+	tokens []queryToken
+	idx    int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.idx]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.tokens[p.idx]
+	if tok.kind != queryTokenEOF {
+		p.idx++
+	}
+	return tok
+}
+
+func (p *queryParser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == queryTokenKeyword && tok.text == keyword
+}
+
+func (p *queryParser) isSymbol(symbol string) bool {
+	tok := p.peek()
+	return tok.kind == queryTokenSymbol && tok.text == symbol
+}
+
+func (p *queryParser) expectKeyword(keyword string) error {
+	if !p.isKeyword(keyword) {
+		return p.errorf(p.peek(), "expect %s, got %s", keyword, p.peek().describe())
+	}
+	p.next()
+	return nil
+}
+
+func (p *queryParser) expectSymbol(symbol string) error {
+	if !p.isSymbol(symbol) {
+		return p.errorf(p.peek(), "expect %q, got %s", symbol, p.peek().describe())
+	}
+	p.next()
+	return nil
+}
+
+func (p *queryParser) errorf(tok queryToken, format string, args ...interface{}) error {
+	return &QuerySyntaxError{Lang: p.lang, Query: p.query, Pos: tok.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// parseOr operand joined by or and and keyword, and binds tighter than or
+func (p *queryParser) parseOr(or, and string, operand func() (interface{}, error)) (interface{}, error) {
+	return p.parseJoin(or, func() (interface{}, error) {
+		return p.parseJoin(and, operand)
+	})
+}
+
+// parseJoin operand [keyword operand]..., and keyword is must of items, or keyword is should of items
+func (p *queryParser) parseJoin(keyword string, operand func() (interface{}, error)) (interface{}, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	items := []interface{}{left}
+	for p.isKeyword(keyword) {
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, right)
+	}
+	if len(items) == 1 {
+		return left, nil
+	}
+	if strings.EqualFold(keyword, "or") {
+		return boolFilter{Bool: esQueryBool{Should: items, MinimumShouldMatch: "1"}}, nil
+	}
+	return boolFilter{Bool: esQueryBool{Must: items}}, nil
+}
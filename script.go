@@ -0,0 +1,22 @@
+package ges
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+
+***************************/
+
+// Script inline script(Source) or stored script(ID)
+type Script struct {
+	Source string `json:"source,omitempty"`
+	ID     string `json:"id,omitempty"`
+	// Lang default painless
+	Lang   string                 `json:"lang,omitempty"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// NewScript painless inline script
+func NewScript(source string, params map[string]interface{}) Script {
+	return Script{Source: source, Params: params}
+}
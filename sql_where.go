@@ -0,0 +1,450 @@
+package ges
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+		sql where clause to Filter. eg: status = ? AND (age BETWEEN ? AND ?) OR name LIKE ?
+		support =, !=, <>, <, <=, >, >=, [NOT] IN, [NOT] BETWEEN, [NOT] LIKE, IS [NOT] NULL, AND, OR, NOT, ()
+
+***************************/
+
+// sqlLang Lang of sql where syntax error
+const sqlLang = "sql where"
+
+var sqlKeywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "IN": true, "BETWEEN": true,
+	"LIKE": true, "IS": true, "NULL": true, "TRUE": true, "FALSE": true,
+}
+
+var sqlSymbols = map[string]bool{
+	"=": true, "!=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true,
+	"(": true, ")": true, ",": true, "-": true,
+}
+
+func sqlTokenize(query string) ([]queryToken, error) {
+	runes := []rune(query)
+	tokens := make([]queryToken, 0, len(runes)/2)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '?':
+			tokens = append(tokens, queryToken{kind: queryTokenParam, text: "?", pos: pos})
+			i++
+		case r == '\'':
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, &QuerySyntaxError{Lang: sqlLang, Query: query, Pos: pos, Msg: "unterminated string"}
+				}
+				if runes[i] == '\'' {
+					// '' is escaped quote
+					if i+1 < len(runes) && runes[i+1] == '\'' {
+						sb.WriteRune('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenString, text: sb.String(), pos: pos})
+		case r == '`' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, &QuerySyntaxError{Lang: sqlLang, Query: query, Pos: pos, Msg: "unterminated quoted identifier"}
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenWord, text: string(runes[i+1 : end]), pos: pos})
+			i = end + 1
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || runes[end] == '.' || runes[end] == 'e' || runes[end] == 'E' ||
+				((runes[end] == '-' || runes[end] == '+') && (runes[end-1] == 'e' || runes[end-1] == 'E'))) {
+				end++
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenNumber, text: string(runes[i:end]), pos: pos})
+			i = end
+		case unicode.IsLetter(r) || r == '_' || r == '@':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || strings.ContainsRune("_.@", runes[end])) {
+				end++
+			}
+			word := string(runes[i:end])
+			if sqlKeywords[strings.ToUpper(word)] {
+				tokens = append(tokens, queryToken{kind: queryTokenKeyword, text: strings.ToUpper(word), pos: pos})
+			} else {
+				tokens = append(tokens, queryToken{kind: queryTokenWord, text: word, pos: pos})
+			}
+			i = end
+		default:
+			symbol := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "!=", "<>", "<=", ">=":
+					symbol = two
+				}
+			}
+			if !sqlSymbols[symbol] {
+				return nil, &QuerySyntaxError{Lang: sqlLang, Query: query, Pos: pos, Msg: fmt.Sprintf("unexpected character %q", r)}
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenSymbol, text: symbol, pos: pos})
+			i += len([]rune(symbol))
+		}
+	}
+	tokens = append(tokens, queryToken{kind: queryTokenEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
+
+type sqlParser struct {
+	queryParser
+	args   []interface{}
+	argIdx int
+}
+
+// ParseSQLWhere parse sql where clause with ? placeholder bound to args in order. eg:
+// ParseSQLWhere("status = ? AND age BETWEEN ? AND ? AND tag IN (?)", 1, 18, 30, []string{"a", "b"})
+// syntax error is *QuerySyntaxError
+func ParseSQLWhere(query string, args ...interface{}) (Filter, error) {
+	tokens, err := sqlTokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{queryParser: queryParser{lang: sqlLang, query: query, tokens: tokens}, args: args}
+	if p.peek().kind == queryTokenEOF {
+		return nil, p.errorf(p.peek(), "empty where clause")
+	}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != queryTokenEOF {
+		return nil, p.errorf(tok, "unexpected %s", tok.describe())
+	}
+	if p.argIdx != len(p.args) {
+		return nil, p.errorf(p.peek(), "%d placeholders but %d args", p.argIdx, len(p.args))
+	}
+	return schemaFilter(node), nil
+}
+
+func (p *sqlParser) parseOr() (interface{}, error) {
+	return p.queryParser.parseOr("OR", "AND", p.parseNot)
+}
+
+func (p *sqlParser) parseNot() (interface{}, error) {
+	if p.isKeyword("NOT") {
+		p.next()
+		item, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return sqlNot(item), nil
+	}
+	if p.isSymbol("(") {
+		p.next()
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		return item, nil
+	}
+	return p.parsePredicate()
+}
+
+func sqlNot(item interface{}) interface{} {
+	return boolFilter{Bool: esQueryBool{Not: []interface{}{item}}}
+}
+
+func (p *sqlParser) parsePredicate() (interface{}, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != queryTokenWord {
+		return nil, p.errorf(fieldTok, "expect field name, got %s", fieldTok.describe())
+	}
+	field := fieldTok.text
+
+	tok := p.peek()
+	if tok.kind == queryTokenSymbol {
+		switch tok.text {
+		case "=", "!=", "<>", "<", "<=", ">", ">=":
+			p.next()
+			value, err := p.parseScalar()
+			if err != nil {
+				return nil, err
+			}
+			return sqlCompare(field, tok.text, value), nil
+		}
+	}
+
+	not := false
+	if p.isKeyword("IS") {
+		p.next()
+		if p.isKeyword("NOT") {
+			p.next()
+			not = true
+		}
+		if err := p.expectKeyword("NULL"); err != nil {
+			return nil, err
+		}
+		if not {
+			return exists{name: field}, nil
+		}
+		return sqlNot(exists{name: field}), nil
+	}
+	if p.isKeyword("NOT") {
+		p.next()
+		not = true
+	}
+	var item interface{}
+	switch {
+	case p.isKeyword("IN"):
+		p.next()
+		values, err := p.parseInValues()
+		if err != nil {
+			return nil, err
+		}
+		item = terms{name: field, values: values}
+	case p.isKeyword("BETWEEN"):
+		p.next()
+		start, err := p.parseScalar()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		end, err := p.parseScalar()
+		if err != nil {
+			return nil, err
+		}
+		b := between{name: field}
+		b.GtePtr, b.LtePtr = rangeValue(start), rangeValue(end)
+		item = b
+	case p.isKeyword("LIKE"):
+		p.next()
+		valueTok := p.peek()
+		value, err := p.parseScalar()
+		if err != nil {
+			return nil, err
+		}
+		pattern, ok := value.(string)
+		if !ok {
+			return nil, p.errorf(valueTok, "LIKE pattern must be string, got %T", value)
+		}
+		w := wildCard{name: field}
+		w.Wildcard = sqlLikeToWildcard(pattern)
+		item = w
+	default:
+		return nil, p.errorf(p.peek(), "expect operator after field %s, got %s", field, p.peek().describe())
+	}
+	if not {
+		return sqlNot(item), nil
+	}
+	return item, nil
+}
+
+func sqlCompare(field, op string, value interface{}) interface{} {
+	switch op {
+	case "=":
+		return term{name: field, value: value}
+	case "!=", "<>":
+		return sqlNot(term{name: field, value: value})
+	}
+	return schemaRange(field, op, value)
+}
+
+// sqlLikeToWildcard % to *, _ to ?, wildcard characters of pattern escaped
+func sqlLikeToWildcard(pattern string) string {
+	var sb strings.Builder
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			sb.WriteRune('*')
+		case '_':
+			sb.WriteRune('?')
+		case '*', '?', '\\':
+			sb.WriteRune('\\')
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// parseInValues (v1, v2, ...), single placeholder bound to slice arg expand to values
+func (p *sqlParser) parseInValues() ([]interface{}, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	for {
+		tok := p.peek()
+		if tok.kind == queryTokenParam && p.argIdx < len(p.args) && isSQLSliceArg(p.args[p.argIdx]) {
+			p.next()
+			items, err := sqlSliceArg(p.args[p.argIdx])
+			if err != nil {
+				return nil, p.errorf(tok, "arg %d %s", p.argIdx+1, err.Error())
+			}
+			p.argIdx++
+			values = append(values, items...)
+		} else {
+			value, err := p.parseScalar()
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		if p.isSymbol(",") {
+			p.next()
+			continue
+		}
+		if err := p.expectSymbol(")"); err != nil {
+			return nil, err
+		}
+		break
+	}
+	if len(values) == 0 {
+		return nil, p.errorf(p.peek(), "IN values is empty")
+	}
+	return values, nil
+}
+
+// parseScalar placeholder or literal
+func (p *sqlParser) parseScalar() (interface{}, error) {
+	tok := p.next()
+	switch tok.kind {
+	case queryTokenParam:
+		if p.argIdx >= len(p.args) {
+			return nil, p.errorf(tok, "placeholder %d has no arg", p.argIdx+1)
+		}
+		value, err := sqlScalarArg(p.args[p.argIdx])
+		if err != nil {
+			return nil, p.errorf(tok, "arg %d %s", p.argIdx+1, err.Error())
+		}
+		p.argIdx++
+		return value, nil
+	case queryTokenString:
+		return tok.text, nil
+	case queryTokenNumber:
+		return p.parseNumber(tok, "")
+	case queryTokenKeyword:
+		switch tok.text {
+		case "TRUE":
+			return true, nil
+		case "FALSE":
+			return false, nil
+		case "NULL":
+			return nil, p.errorf(tok, "compare with NULL, use IS NULL or IS NOT NULL")
+		}
+	case queryTokenSymbol:
+		if tok.text == "-" && p.peek().kind == queryTokenNumber {
+			return p.parseNumber(p.next(), "-")
+		}
+	}
+	return nil, p.errorf(tok, "expect value, got %s", tok.describe())
+}
+
+func (p *sqlParser) parseNumber(tok queryToken, sign string) (interface{}, error) {
+	if i, err := strconv.ParseInt(sign+tok.text, 10, 64); err == nil {
+		return i, nil
+	}
+	f, err := strconv.ParseFloat(sign+tok.text, 64)
+	if err != nil {
+		return nil, p.errorf(tok, "invalid number %s", tok.text)
+	}
+	return f, nil
+}
+
+func isSQLSliceArg(arg interface{}) bool {
+	if arg == nil {
+		return false
+	}
+	kind := reflect.TypeOf(arg).Kind()
+	// []byte is string like value
+	return (kind == reflect.Slice || kind == reflect.Array) && reflect.TypeOf(arg).Elem().Kind() != reflect.Uint8
+}
+
+func sqlSliceArg(arg interface{}) ([]interface{}, error) {
+	v := reflect.ValueOf(arg)
+	values := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		value, err := sqlScalarArg(v.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("is empty slice")
+	}
+	return values, nil
+}
+
+// sqlScalarArg arg must be string, number, bool or time.Time, pointer is dereferenced
+func sqlScalarArg(arg interface{}) (interface{}, error) {
+	if arg == nil {
+		return nil, fmt.Errorf("is nil, use IS NULL or IS NOT NULL")
+	}
+	if t, ok := arg.(time.Time); ok {
+		return t, nil
+	}
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("is nil, use IS NULL or IS NOT NULL")
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes()), nil
+		}
+	case reflect.Struct:
+		if t, ok := v.Interface().(time.Time); ok {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("type %T not support, must be string, number, bool or time.Time", arg)
+}
+
+// SQLWhere sql where clause as Where conditions, syntax or args error returned by Search, Count, Delete
+func (e es) SQLWhere(query string, args ...interface{}) Client {
+	f, err := ParseSQLWhere(query, args...)
+	if err != nil {
+		e = e.Clone()
+		e.err = err
+		return e
+	}
+	return e.Where(f)
+}
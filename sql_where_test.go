@@ -0,0 +1,68 @@
+package ges
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+/***************************
+    @author: tiansheng.ren
+    @date: 2026/10/18
+    @desc:
+
+***************************/
+
+func TestParseSQLWhere(t *testing.T) {
+	f, err := ParseSQLWhere("status = ? AND (age BETWEEN ? AND ?) OR name LIKE ?", 1, 18, 30, "jo%")
+	require.NoError(t, err, "TestParseSQLWhere ParseSQLWhere")
+	actual, err := json.Marshal(f.Result())
+	require.NoError(t, err, "TestParseSQLWhere json.Marshal")
+	expected := `[{"bool":{"should":[` +
+		`{"bool":{"must":[{"term":{"status":1}},{"range":{"age":{"lte":30,"gte":18}}}]}},` +
+		`{"wildcard":{"name":{"wildcard":"jo*"}}}],"minimum_should_match":"1"}}]`
+	require.Equal(t, expected, string(actual), "TestParseSQLWhere and or")
+
+	f, err = ParseSQLWhere("tag IN (?) and id not in (1, 2) AND deleted_at IS NULL AND NOT level <> 'a''b' and score >= -1.5",
+		[]string{"go", "es"})
+	require.NoError(t, err, "TestParseSQLWhere ParseSQLWhere")
+	actual, err = json.Marshal(ES().Where(f))
+	require.NoError(t, err, "TestParseSQLWhere json.Marshal")
+	expected = `{"query":{"bool":{"must":[` +
+		`{"terms":{"tag":["go","es"]}},` +
+		`{"bool":{"must_not":[{"terms":{"id":[1,2]}}]}},` +
+		`{"bool":{"must_not":[{"exists":{"field":"deleted_at"}}]}},` +
+		`{"bool":{"must_not":[{"bool":{"must_not":[{"term":{"level":"a'b"}}]}}]}},` +
+		`{"range":{"score":{"gte":-1.5}}}]}}}`
+	require.Equal(t, expected, string(actual), "TestParseSQLWhere in null not")
+}
+
+func TestParseSQLWhereError(t *testing.T) {
+	for query, pos := range map[string]int{
+		"status = ? AND":     15,
+		"status == 1":        9,
+		"(status = 1":        12,
+		"name = 'abc":        8,
+		"status = NULL":      10,
+		"age BETWEEN 1 2":    15,
+		"status = 1 age = 2": 12,
+		"status # 1":         8,
+	} {
+		_, err := ParseSQLWhere(query, 1)
+		syntaxErr := &QuerySyntaxError{}
+		require.True(t, errors.As(err, &syntaxErr), "TestParseSQLWhereError "+query)
+		require.Equal(t, pos, syntaxErr.Pos, "TestParseSQLWhereError position "+query)
+		require.Equal(t, sqlLang, syntaxErr.Lang, "TestParseSQLWhereError lang "+query)
+	}
+
+	_, err := ParseSQLWhere("status = ?", map[string]int{})
+	require.Error(t, err, "TestParseSQLWhereError arg type")
+	_, err = ParseSQLWhere("status = ?", 1, 2)
+	syntaxErr := &QuerySyntaxError{}
+	require.True(t, errors.As(err, &syntaxErr), "TestParseSQLWhereError arg count")
+	require.Equal(t, 11, syntaxErr.Pos, "TestParseSQLWhereError arg count position")
+
+	_, err = json.Marshal(ES().SQLWhere("status = = 1").Size(10))
+	require.Error(t, err, "TestParseSQLWhereError client deferred error")
+}